@@ -0,0 +1,603 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/gstackio/go-patch/patch"
+)
+
+const usage = `Usage: go-patch <command> [options] [args]
+
+Commands:
+  apply        [-o ops.yml]... [-v key=val]... [-l vars.yml]... [--var-errs] [--idempotent] base.yml
+  diff         [--unchecked] [--ignore /path]... [--include /path]... [--format yaml|text] [--color] [--group-depth n] [--redact] left.yml right.yml
+  explain      [-o ops.yml]... [-v key=val]... [-l vars.yml]... [--var-errs] [--idempotent] [--format text|yaml|json] [--redact] base.yml
+  find         [-o ops.yml]... [-v key=val]... [-l vars.yml]... [--var-errs] [--idempotent] /path doc.yml
+  idempotence  [-o ops.yml]... [-v key=val]... [-l vars.yml]... [--var-errs] [--idempotent] base.yml
+  validate     [-v key=val]... [-l vars.yml]... [--var-errs] ops.yml...
+
+YAML and JSON files are accepted; '-' reads from stdin.
+`
+
+var errUsage = errors.New("Invalid usage")
+
+type CLI struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewCLI(stdin io.Reader, stdout, stderr io.Writer) CLI {
+	return CLI{stdin: stdin, stdout: stdout, stderr: stderr}
+}
+
+func (c CLI) Run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(c.stderr, usage)
+		return 1
+	}
+
+	var err error
+
+	switch args[0] {
+	case "apply":
+		err = c.apply(args[1:])
+	case "diff":
+		err = c.diff(args[1:])
+	case "explain":
+		err = c.explain(args[1:])
+	case "find":
+		err = c.find(args[1:])
+	case "idempotence":
+		err = c.idempotence(args[1:])
+	case "validate":
+		err = c.validate(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(c.stdout, usage)
+		return 0
+	default:
+		err = fmt.Errorf("Unknown command '%s'", args[0])
+	}
+
+	if err != nil {
+		if err != errUsage && err != flag.ErrHelp {
+			fmt.Fprintf(c.stderr, "Error: %s\n", err)
+		}
+		if err != flag.ErrHelp {
+			fmt.Fprint(c.stderr, usage)
+		}
+		return 1
+	}
+
+	return 0
+}
+
+func (c CLI) apply(args []string) error {
+	var opts varsOpts
+
+	fs := c.newFlagSet("apply")
+	opts.register(fs, true)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	result, err := c.applyOps(fs.Arg(0), opts)
+	if err != nil {
+		return err
+	}
+
+	return c.writeYAML(result)
+}
+
+func (c CLI) diff(args []string) error {
+	fs := c.newFlagSet("diff")
+	unchecked := fs.Bool("unchecked", false, "Skip test operations")
+
+	var ignoreStrs, includeStrs stringsFlag
+	fs.Var(&ignoreStrs, "ignore", "Skip paths matching pattern (multiple allowed)")
+	fs.Var(&includeStrs, "include", "Only compare paths matching pattern (multiple allowed)")
+
+	format := fs.String("format", "yaml", "Output format (yaml operations or text report)")
+	color := fs.Bool("color", false, "Color text report")
+	groupDepth := fs.Int("group-depth", 0, "Group text report by leading path tokens")
+	redact := fs.Bool("redact", false, "Hide sensitive-looking values (e.g. passwords)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return errUsage
+	}
+
+	switch *format {
+	case "yaml", "text":
+	default:
+		return fmt.Errorf("Unknown format '%s'", *format)
+	}
+
+	ignore, err := c.parsePointers(ignoreStrs)
+	if err != nil {
+		return err
+	}
+
+	include, err := c.parsePointers(includeStrs)
+	if err != nil {
+		return err
+	}
+
+	left, err := c.readDoc(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	right, err := c.readDoc(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	diff := patch.Diff{Left: left, Right: right, Unchecked: *unchecked, Ignore: ignore, Include: include}
+
+	if *format == "text" {
+		renderer := patch.DiffRenderer{Color: *color, GroupDepth: *groupDepth}
+		if *redact {
+			renderer.Redact = patch.DefaultRedactionPolicy().Redacts
+		}
+
+		fmt.Fprint(c.stdout, renderer.Render(diff))
+		return nil
+	}
+
+	ops, ignored := diff.CalculateWithIgnored()
+
+	for _, path := range ignored {
+		fmt.Fprintf(c.stderr, "Ignored difference at '%s'\n", path)
+	}
+
+	var opDefs []patch.OpDefinition
+
+	if *redact {
+		opDefs, err = patch.NewRedactedOpDefinitionsFromOps(ops, patch.DefaultRedactionPolicy())
+	} else {
+		opDefs, err = patch.NewOpDefinitionsFromOps(ops)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.writeYAML(opDefs)
+}
+
+func (CLI) parsePointers(strs []string) ([]patch.Pointer, error) {
+	var ptrs []patch.Pointer
+
+	for _, str := range strs {
+		ptr, err := patch.NewPointerFromString(str)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid pattern '%s': %s", str, err)
+		}
+		ptrs = append(ptrs, ptr)
+	}
+
+	return ptrs, nil
+}
+
+type explainedOpsFile struct {
+	OpsFile string        `json:"ops_file" yaml:"ops_file"`
+	Changes patch.Changes `json:"changes" yaml:"changes"`
+}
+
+func (c CLI) explain(args []string) error {
+	var opts varsOpts
+
+	fs := c.newFlagSet("explain")
+	opts.register(fs, true)
+	format := fs.String("format", "text", "Output format (text, yaml or json)")
+	redact := fs.Bool("redact", false, "Hide sensitive-looking values (e.g. passwords)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	switch *format {
+	case "text", "yaml", "json":
+	default:
+		return fmt.Errorf("Unknown format '%s'", *format)
+	}
+
+	vars, err := opts.variables(c)
+	if err != nil {
+		return err
+	}
+
+	doc, err := c.readBaseDoc(fs.Arg(0), opts, vars)
+	if err != nil {
+		return err
+	}
+
+	var explained []explainedOpsFile
+
+	for _, path := range opts.opsFiles {
+		ops, err := c.readOps(path, opts, vars)
+		if err != nil {
+			return err
+		}
+
+		var changes patch.Changes
+
+		doc, changes, err = ops.Explain(doc)
+		if err != nil {
+			return fmt.Errorf("Applying '%s': %s", path, err)
+		}
+
+		if *redact {
+			changes = changes.Redact(patch.DefaultRedactionPolicy())
+		}
+
+		explained = append(explained, explainedOpsFile{path, changes})
+	}
+
+	switch *format {
+	case "yaml":
+		return c.writeYAML(explained)
+
+	case "json":
+		bytes, err := json.MarshalIndent(explained, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Serializing result: %s", err)
+		}
+
+		_, err = fmt.Fprintf(c.stdout, "%s\n", bytes)
+
+		return err
+	}
+
+	for _, file := range explained {
+		for _, change := range file.Changes {
+			fmt.Fprintf(c.stdout, "%s: %s\n", file.OpsFile, change)
+		}
+	}
+
+	return nil
+}
+
+func (c CLI) find(args []string) error {
+	var opts varsOpts
+
+	fs := c.newFlagSet("find")
+	opts.register(fs, true)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return errUsage
+	}
+
+	ptr, err := patch.NewPointerFromString(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("Invalid path '%s': %s", fs.Arg(0), err)
+	}
+
+	doc, err := c.applyOps(fs.Arg(1), opts)
+	if err != nil {
+		return err
+	}
+
+	result, err := patch.FindOp{Path: ptr}.Apply(doc)
+	if err != nil {
+		return err
+	}
+
+	return c.writeYAML(result)
+}
+
+func (c CLI) idempotence(args []string) error {
+	var opts varsOpts
+
+	fs := c.newFlagSet("idempotence")
+	opts.register(fs, true)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	vars, err := opts.variables(c)
+	if err != nil {
+		return err
+	}
+
+	doc, err := c.readBaseDoc(fs.Arg(0), opts, vars)
+	if err != nil {
+		return err
+	}
+
+	var failed bool
+
+	// Each ops file is checked against the document produced by previous ops files
+	for _, path := range opts.opsFiles {
+		ops, err := c.readOps(path, opts, vars)
+		if err != nil {
+			return err
+		}
+
+		violations, err := patch.CheckIdempotence(ops, doc)
+		if err != nil {
+			return fmt.Errorf("Applying '%s': %s", path, err)
+		}
+
+		for _, violation := range violations {
+			fmt.Fprintf(c.stdout, "%s: %s\n", path, violation)
+			failed = true
+		}
+
+		if len(violations) == 0 {
+			fmt.Fprintf(c.stdout, "%s: OK\n", path)
+		}
+
+		doc, err = ops.Apply(doc)
+		if err != nil {
+			return fmt.Errorf("Applying '%s': %s", path, err)
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("Non-idempotent operations files")
+	}
+
+	return nil
+}
+
+func (c CLI) validate(args []string) error {
+	var opts varsOpts
+
+	fs := c.newFlagSet("validate")
+	opts.register(fs, false)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+
+	vars, err := opts.variables(c)
+	if err != nil {
+		return err
+	}
+
+	var failed bool
+
+	for _, path := range fs.Args() {
+		ops, err := c.readOps(path, opts, vars)
+		if err != nil {
+			fmt.Fprintf(c.stdout, "%s: %s\n", path, err)
+			failed = true
+			continue
+		}
+
+		// Warnings are reported but do not fail validation
+		for _, finding := range patch.AnalyzeOps(ops) {
+			fmt.Fprintf(c.stdout, "%s: %s\n", path, finding)
+		}
+
+		fmt.Fprintf(c.stdout, "%s: OK\n", path)
+	}
+
+	if failed {
+		return fmt.Errorf("Invalid operations files")
+	}
+
+	return nil
+}
+
+func (c CLI) applyOps(docPath string, opts varsOpts) (interface{}, error) {
+	vars, err := opts.variables(c)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := c.readBaseDoc(docPath, opts, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range opts.opsFiles {
+		ops, err := c.readOps(path, opts, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err = ops.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("Applying '%s': %s", path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func (c CLI) readBaseDoc(path string, opts varsOpts, vars patch.StaticVariables) (interface{}, error) {
+	doc, err := c.readDoc(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vars) > 0 || opts.varErrs {
+		doc, err = patch.Interpolator{Vars: vars, AllowMissing: !opts.varErrs}.Interpolate(doc)
+		if err != nil {
+			return nil, fmt.Errorf("Interpolating '%s': %s", path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func (c CLI) readOps(path string, opts varsOpts, vars patch.StaticVariables) (patch.Ops, error) {
+	bytes, err := c.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var opDefs []patch.OpDefinition
+
+	err = yaml.Unmarshal(bytes, &opDefs)
+	if err != nil {
+		return nil, fmt.Errorf("Deserializing ops file '%s': %s", path, err)
+	}
+
+	opDefs, err = patch.Interpolator{Vars: vars, AllowMissing: !opts.varErrs}.InterpolateOpDefinitions(opDefs)
+	if err != nil {
+		return nil, fmt.Errorf("Interpolating ops file '%s': %s", path, err)
+	}
+
+	ops, err := patch.NewOpsFromDefinitionsWithSchemaLoader(opDefs, patch.NewSchemaDirLoader(filepath.Dir(path)))
+	if err != nil {
+		return nil, fmt.Errorf("Building ops from '%s': %s", path, err)
+	}
+
+	if opts.idempotent {
+		ops = ops.Idempotent()
+	}
+
+	return ops, nil
+}
+
+func (c CLI) readDoc(path string) (interface{}, error) {
+	bytes, err := c.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	// JSON is a subset of YAML hence both formats are supported
+	err = yaml.Unmarshal(bytes, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("Deserializing '%s': %s", path, err)
+	}
+
+	return doc, nil
+}
+
+func (c CLI) readFile(path string) ([]byte, error) {
+	var bytes []byte
+	var err error
+
+	if path == "-" {
+		bytes, err = ioutil.ReadAll(c.stdin)
+	} else {
+		bytes, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Reading '%s': %s", path, err)
+	}
+
+	return bytes, nil
+}
+
+func (c CLI) writeYAML(obj interface{}) error {
+	bytes, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("Serializing result: %s", err)
+	}
+
+	_, err = c.stdout.Write(bytes)
+
+	return err
+}
+
+func (c CLI) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+type varsOpts struct {
+	opsFiles   stringsFlag
+	vars       stringsFlag
+	varsFiles  stringsFlag
+	varErrs    bool
+	idempotent bool
+}
+
+func (o *varsOpts) register(fs *flag.FlagSet, withOps bool) {
+	if withOps {
+		fs.Var(&o.opsFiles, "o", "Load operations file (multiple allowed)")
+		fs.Var(&o.opsFiles, "ops-file", "Load operations file (multiple allowed)")
+		fs.BoolVar(&o.idempotent, "idempotent", false, "Skip appending or inserting values that are already present")
+	}
+	fs.Var(&o.vars, "v", "Set variable as key=val (multiple allowed)")
+	fs.Var(&o.vars, "var", "Set variable as key=val (multiple allowed)")
+	fs.Var(&o.varsFiles, "l", "Load variables from a YAML file (multiple allowed)")
+	fs.Var(&o.varsFiles, "vars-file", "Load variables from a YAML file (multiple allowed)")
+	fs.BoolVar(&o.varErrs, "var-errs", false, "Expect all variables to be found")
+}
+
+// variables merges variables files and individual variables (later ones win);
+// it is called once per command since variables files may be read from stdin
+func (o varsOpts) variables(c CLI) (patch.StaticVariables, error) {
+	vars := patch.StaticVariables{}
+
+	for _, path := range o.varsFiles {
+		bytes, err := c.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var fileVars map[string]interface{}
+
+		err = yaml.Unmarshal(bytes, &fileVars)
+		if err != nil {
+			return nil, fmt.Errorf("Deserializing variables file '%s': %s", path, err)
+		}
+
+		for k, v := range fileVars {
+			vars[k] = v
+		}
+	}
+
+	for _, kv := range o.vars {
+		pieces := strings.SplitN(kv, "=", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("Expected variable '%s' to be in key=val format", kv)
+		}
+
+		vars[pieces[0]] = pieces[1]
+	}
+
+	return vars, nil
+}
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringsFlag) Set(val string) error {
+	*f = append(*f, val)
+	return nil
+}
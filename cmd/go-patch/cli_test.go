@@ -0,0 +1,347 @@
+package main_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/cmd/go-patch"
+)
+
+var _ = Describe("CLI", func() {
+	var (
+		dir            string
+		stdin          *bytes.Buffer
+		stdout, stderr *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "go-patch-cli")
+		Expect(err).ToNot(HaveOccurred())
+
+		stdin = bytes.NewBufferString("")
+		stdout = bytes.NewBufferString("")
+		stderr = bytes.NewBufferString("")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		err := ioutil.WriteFile(path, []byte(content), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		return path
+	}
+
+	run := func(args ...string) int {
+		return NewCLI(stdin, stdout, stderr).Run(args)
+	}
+
+	Describe("apply", func() {
+		It("applies ops files in order with variables", func() {
+			base := writeFile("base.yml", "instance_groups:\n- name: api\n  instances: 1\nname: ((name))\n")
+			ops1 := writeFile("ops1.yml", "- type: replace\n  path: /instance_groups/name=((ig))/instances\n  value: ((instances))\n")
+			ops2 := writeFile("ops2.yml", "- type: replace\n  path: /new?\n  value: true\n")
+
+			code := run("apply", "-o", ops1, "--ops-file", ops2, "-v", "ig=api", "--var", "instances=2", "-v", "name=dep", base)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("instance_groups:\n- instances: \"2\"\n  name: api\nname: dep\nnew: true\n"))
+		})
+
+		It("reads JSON documents and variables files", func() {
+			base := writeFile("base.json", `{"a": {"b": 1}}`)
+			ops := writeFile("ops.json", `[{"type": "replace", "path": "/a/b", "value": "((val))"}]`)
+			vars := writeFile("vars.yml", "val: {c: 2}\n")
+
+			code := run("apply", "-o", ops, "-l", vars, base)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("a:\n  b:\n    c: 2\n"))
+		})
+
+		It("reads document from stdin", func() {
+			stdin.WriteString("a: 1\n")
+			ops := writeFile("ops.yml", "- type: remove\n  path: /a\n")
+
+			code := run("apply", "-o", ops, "-")
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("{}\n"))
+		})
+
+		It("reads variables from stdin once for all ops files", func() {
+			stdin.WriteString("a: 1\nb: 2\n")
+			base := writeFile("base.yml", "x: ((a))\n")
+			ops1 := writeFile("ops1.yml", "- type: replace\n  path: /c?\n  value: ((a))\n")
+			ops2 := writeFile("ops2.yml", "- type: replace\n  path: /d?\n  value: ((b))\n")
+
+			code := run("apply", "-o", ops1, "-o", ops2, "-l", "-", "--var-errs", base)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("c: 1\nd: 2\nx: 1\n"))
+		})
+
+		It("leaves missing variables unless expected", func() {
+			base := writeFile("base.yml", "a: ((a))\n")
+
+			code := run("apply", base)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("a: ((a))\n"))
+
+			code = run("apply", "--var-errs", base)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("Error: Interpolating '" + base + "': Expected to find variables: a"))
+		})
+
+		It("skips appending values that are already present if requested", func() {
+			base := writeFile("base.yml", "azs: [z1]\n")
+			ops := writeFile("ops.yml", "- type: replace\n  path: /azs/-\n  value: z1\n")
+
+			code := run("apply", "-o", ops, "--idempotent", base)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("azs:\n- z1\n"))
+		})
+
+		It("loads schemas relative to ops file", func() {
+			Expect(os.Mkdir(filepath.Join(dir, "schemas"), 0700)).To(Succeed())
+
+			base := writeFile("base.yml", "a: 1\n")
+			writeFile("schemas/a.yml", "type: string\n")
+			ops := writeFile("ops.yml", "- type: validate\n  path: /a\n  schema: schemas/a.yml\n")
+
+			code := run("apply", "-o", ops, base)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("'/a': Expected value of type 'string' but found 'integer'"))
+
+			ops = writeFile("ops.yml", "- type: validate\n  path: /a\n  schema: ../a.yml\n")
+
+			code = run("apply", "-o", ops, base)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("Invalid schema: Expected schema path '../a.yml' to be within '" + dir + "'"))
+		})
+
+		It("returns an error if operation fails", func() {
+			base := writeFile("base.yml", "a: 1\n")
+			ops := writeFile("ops.yml", "- type: remove\n  path: /b\n")
+
+			code := run("apply", "-o", ops, base)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("Error: Applying '" + ops + "': Expected to find a map key 'b' for path '/b'"))
+		})
+
+		It("requires base document", func() {
+			code := run("apply")
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(HavePrefix("Usage: go-patch"))
+		})
+	})
+
+	Describe("diff", func() {
+		It("prints operations converting left document into right document", func() {
+			left := writeFile("left.yml", "a: 1\nb: 2\n")
+			right := writeFile("right.json", `{"a": 3, "b": 2}`)
+
+			code := run("diff", left, right)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("- type: test\n  path: /a\n  value: 1\n- type: replace\n  path: /a\n  value: 3\n"))
+		})
+
+		It("skips test operations if requested", func() {
+			left := writeFile("left.yml", "a: 1\n")
+			right := writeFile("right.yml", "a: 3\n")
+
+			code := run("diff", "--unchecked", left, right)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("- type: replace\n  path: /a\n  value: 3\n"))
+		})
+
+		It("skips ignored paths and reports ignored differences", func() {
+			left := writeFile("left.yml", "a: 1\nb: {ts: 1, c: 1}\n")
+			right := writeFile("right.yml", "a: 2\nb: {ts: 2, c: 2}\n")
+
+			code := run("diff", "--unchecked", "--include", "/b", "--ignore", "/*/ts", left, right)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("- type: replace\n  path: /b/c\n  value: 2\n"))
+			Expect(stderr.String()).To(Equal("Ignored difference at '/b/ts'\n"))
+		})
+
+		It("prints text report if requested", func() {
+			left := writeFile("left.yml", "a: 1\nb: {c: 1}\n")
+			right := writeFile("right.yml", "a: 2\nb: {c: 2, d: 3}\n")
+
+			code := run("diff", "--format", "text", "--group-depth", "1", left, right)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("~ /a: 1 → 2\n/b:\n  ~ /c: 1 → 2\n  + /d: 3\n"))
+
+			code = run("diff", "--format", "html", left, right)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("Unknown format 'html'"))
+		})
+
+		It("hides sensitive-looking values if requested", func() {
+			left := writeFile("left.yml", "user: a\npassword: old\n")
+			right := writeFile("right.yml", "user: b\npassword: new\n")
+
+			code := run("diff", "--unchecked", "--redact", left, right)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("- type: replace\n  path: /password\n  value: <redacted>\n- type: replace\n  path: /user\n  value: b\n"))
+
+			stdout.Reset()
+
+			code = run("diff", "--format", "text", "--redact", left, right)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("~ /password: <redacted> → <redacted>\n~ /user: a → b\n"))
+		})
+
+		It("returns an error if pattern is invalid", func() {
+			left := writeFile("left.yml", "a: 1\n")
+
+			code := run("diff", "--ignore", "a", left, left)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("Invalid pattern 'a': Expected to start with '/'"))
+		})
+	})
+
+	Describe("explain", func() {
+		It("prints changes made by each ops file", func() {
+			base := writeFile("base.yml", "name: dep\nazs: [z1]\n")
+			ops1 := writeFile("ops1.yml", "- type: replace\n  path: /azs/-\n  value: z2\n")
+			ops2 := writeFile("ops2.yml", "- type: replace\n  path: /name\n  value: ((name))\n  error: rename\n- type: remove\n  path: /azs/0\n")
+
+			code := run("explain", "-o", ops1, "-o", ops2, "-v", "name=new", base)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal(ops1 + `: op 0: appended "z2" to /azs
+` + ops2 + `: rename: replaced /name "dep" → "new"
+` + ops2 + `: op 1: removed /azs/0 (was "z1")
+`))
+		})
+
+		It("prints changes as JSON if requested", func() {
+			base := writeFile("base.yml", "name: dep\n")
+			ops := writeFile("ops.yml", "- type: replace\n  path: /name\n  value: new\n")
+
+			code := run("explain", "-o", ops, "--format", "json", base)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(MatchJSON(`[{"ops_file": "` + ops + `", "changes": [
+				{"index": 0, "label": "op 0", "path": "/name", "kind": "set", "old": "dep", "new": "new"}
+			]}]`))
+		})
+
+		It("hides sensitive-looking values if requested", func() {
+			base := writeFile("base.yml", "password: old\n")
+			ops := writeFile("ops.yml", "- type: replace\n  path: /password\n  value: new\n")
+
+			code := run("explain", "-o", ops, base)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal(ops + `: op 0: replaced /password "old" → "new"` + "\n"))
+
+			stdout.Reset()
+
+			code = run("explain", "-o", ops, "--redact", base)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal(ops + `: op 0: replaced /password "<redacted>" → "<redacted>"` + "\n"))
+		})
+
+		It("returns an error for unknown format", func() {
+			base := writeFile("base.yml", "name: dep\n")
+
+			code := run("explain", "--format", "xml", base)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(HavePrefix("Error: Unknown format 'xml'"))
+		})
+	})
+
+	Describe("find", func() {
+		It("prints found value after applying ops files", func() {
+			doc := writeFile("doc.yml", "items:\n- name: a\n  val: 1\n")
+			ops := writeFile("ops.yml", "- type: replace\n  path: /items/name=a/val\n  value: 2\n")
+
+			code := run("find", "-o", ops, "/items/name=a", doc)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal("name: a\nval: 2\n"))
+		})
+
+		It("returns an error for invalid path", func() {
+			doc := writeFile("doc.yml", "a: 1\n")
+
+			code := run("find", "a", doc)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("Error: Invalid path 'a': Expected to start with '/'"))
+		})
+	})
+
+	Describe("idempotence", func() {
+		It("reports non-idempotent operations of each ops file", func() {
+			base := writeFile("base.yml", "azs: [z1]\n")
+			ops1 := writeFile("ops1.yml", "- type: replace\n  path: /azs/=z2?\n  value: z2\n")
+			ops2 := writeFile("ops2.yml", "- type: replace\n  path: /azs/0\n  value: z0\n- type: replace\n  path: /azs/-\n  value: z3\n")
+
+			code := run("idempotence", "-o", ops1, "-o", ops2, base)
+			Expect(code).To(Equal(1))
+			Expect(stdout.String()).To(Equal(ops1 + ": OK\n" +
+				ops2 + ": Operation [1] for path '/azs/-' changes document when applied again\n"))
+			Expect(stderr.String()).To(HavePrefix("Error: Non-idempotent operations files"))
+		})
+
+		It("succeeds for idempotent ops files", func() {
+			base := writeFile("base.yml", "azs: [z1]\n")
+			ops := writeFile("ops.yml", "- type: replace\n  path: /azs/-\n  value: z3\n")
+
+			code := run("idempotence", "-o", ops, "--idempotent", base)
+			Expect(stderr.String()).To(BeEmpty())
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal(ops + ": OK\n"))
+		})
+	})
+
+	Describe("validate", func() {
+		It("reports validity of each ops file", func() {
+			valid := writeFile("valid.yml", "- type: remove\n  path: /a\n")
+			invalid := writeFile("invalid.yml", "- type: replace\n  path: /a\n")
+
+			code := run("validate", valid, invalid)
+			Expect(code).To(Equal(1))
+
+			lines := strings.Split(stdout.String(), "\n")
+			Expect(lines[0]).To(Equal(valid + ": OK"))
+			Expect(lines[1]).To(HavePrefix(invalid + ": Building ops from '" + invalid + "': Replace operation [0]: Missing value"))
+		})
+
+		It("reports analysis findings of valid ops files", func() {
+			valid := writeFile("valid.yml", "- type: replace\n  path: /a\n  value: 1\n- type: remove\n  path: /a\n")
+
+			code := run("validate", valid)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal(valid + ": Operation [0] warning shadowed: " +
+				"Expected value set at '/a' to be used but it's overwritten by operation [1]\n" + valid + ": OK\n"))
+		})
+
+		It("succeeds for valid ops files", func() {
+			valid := writeFile("valid.yml", "- type: replace\n  path: /((a))\n  value: ((b))\n")
+
+			code := run("validate", valid)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(Equal(valid + ": OK\n"))
+		})
+	})
+
+	It("returns an error for unknown command", func() {
+		code := run("unknown")
+		Expect(code).To(Equal(1))
+		Expect(stderr.String()).To(HavePrefix("Error: Unknown command 'unknown'"))
+	})
+})
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	os.Exit(NewCLI(os.Stdin, os.Stdout, os.Stderr).Run(os.Args[1:]))
+}
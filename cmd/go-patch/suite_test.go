@@ -0,0 +1,13 @@
+package main_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGoPatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "go-patch")
+}
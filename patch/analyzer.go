@@ -0,0 +1,396 @@
+package patch
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type FindingSeverity string
+
+const (
+	FindingError   FindingSeverity = "error"
+	FindingWarning FindingSeverity = "warning"
+)
+
+const (
+	FindingInvalidOperation         = "invalid-operation"
+	FindingKeyModifiers             = "key-modifiers"
+	FindingAfterLastIndexNotLast    = "after-last-index-not-last"
+	FindingNonOptionalAfterOptional = "non-optional-after-optional"
+	FindingOptionalTestValue        = "optional-test-value"
+	FindingDuplicatePath            = "duplicate-path"
+	FindingRemoveThenReplace        = "remove-then-replace"
+	FindingShadowed                 = "shadowed"
+)
+
+// Finding describes a potential problem found in operations without applying them
+type Finding struct {
+	Index    int // index of the top level operation
+	Severity FindingSeverity
+	Code     string
+	Path     string
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("Operation [%d] %s %s: %s", f.Index, f.Severity, f.Code, f.Message)
+}
+
+// AnalyzeOpDefinitions reports invalid operation definitions
+// (using the same checks as NewOpsFromDefinitions) and analyzes valid ones
+func AnalyzeOpDefinitions(opDefs []OpDefinition) []Finding {
+	var findings []Finding
+	var entries []analyzedOp
+
+	// schemas are not needed for analysis
+	p := parser{loadSchema: func(path string) (Schema, error) { return Schema{Source: path}, nil }}
+
+	a := analyzer{}
+
+	for i, opDef := range opDefs {
+		defFindings := a.lintDefinition(i, opDef)
+
+		if len(defFindings) > 0 {
+			findings = append(findings, defFindings...)
+			continue
+		}
+
+		op, kind, err := p.newOp(opDef)
+		if err != nil {
+			msg := err.Error()
+			if len(kind) > 0 {
+				msg = fmt.Sprintf("%s operation: %s", kind, err)
+			}
+
+			var path string
+			if opDef.Path != nil {
+				path = *opDef.Path
+			}
+
+			findings = append(findings, Finding{i, FindingError, FindingInvalidOperation, path, msg})
+			continue
+		}
+
+		entries = a.flatten(entries, i, op, false)
+	}
+
+	return a.sort(append(findings, a.analyze(entries)...))
+}
+
+// AnalyzeOps analyzes operations; nested operations are reported
+// with the index of the top level operation that contains them
+func AnalyzeOps(ops Ops) []Finding {
+	var entries []analyzedOp
+
+	a := analyzer{}
+
+	for i, op := range ops {
+		entries = a.flatten(entries, i, op, false)
+	}
+
+	return a.sort(a.analyze(entries))
+}
+
+type analyzer struct{}
+
+type analyzedOp struct {
+	index       int
+	op          Op
+	path        Pointer
+	conditional bool // within if operation branches
+}
+
+// lintDefinition checks paths for problems that prevent them from being parsed
+func (a analyzer) lintDefinition(i int, opDef OpDefinition) []Finding {
+	var findings []Finding
+
+	if opDef.Path != nil {
+		for _, tok := range strings.Split(*opDef.Path, "/")[1:] {
+			pieces := strings.Split(tok, ":")
+			if len(pieces) < 2 {
+				continue
+			}
+
+			if _, err := strconv.Atoi(pieces[0]); err == nil || strings.Contains(pieces[0], "=") || pieces[0] == "-" {
+				continue
+			}
+
+			msg := fmt.Sprintf("Expected not to find modifiers on key token '%s' in path '%s'", tok, *opDef.Path)
+			findings = append(findings, Finding{i, FindingError, FindingKeyModifiers, *opDef.Path, msg})
+		}
+	}
+
+	if opDef.Test != nil {
+		findings = append(findings, a.lintDefinition(i, *opDef.Test)...)
+	}
+
+	for _, childDef := range append(append([]OpDefinition{}, opDef.Ops...), opDef.Else...) {
+		findings = append(findings, a.lintDefinition(i, childDef)...)
+	}
+
+	return findings
+}
+
+func (a analyzer) flatten(entries []analyzedOp, i int, op Op, conditional bool) []analyzedOp {
+	switch typedOp := op.(type) {
+	case DescriptiveOp:
+		return a.flatten(entries, i, typedOp.Op, conditional)
+
+	case Ops:
+		for _, childOp := range typedOp {
+			entries = a.flatten(entries, i, childOp, conditional)
+		}
+		return entries
+
+	case GroupOp:
+		childOps, err := rebaseOps(typedOp.Ops, typedOp.Path)
+		if err != nil {
+			return entries
+		}
+		return a.flatten(entries, i, childOps, conditional)
+
+	case IfOp:
+		entries = a.flatten(entries, i, typedOp.Test, conditional)
+		entries = a.flatten(entries, i, typedOp.Then, true)
+		return a.flatten(entries, i, typedOp.Else, true)
+
+	default:
+		if path, found := opPath(op); found {
+			entries = append(entries, analyzedOp{index: i, op: op, path: path, conditional: conditional})
+		}
+		return entries
+	}
+}
+
+func (a analyzer) analyze(entries []analyzedOp) []Finding {
+	var findings []Finding
+
+	for _, entry := range entries {
+		findings = append(findings, a.lintPath(entry)...)
+	}
+
+	var sequential []analyzedOp
+
+	for _, entry := range entries {
+		if !entry.conditional {
+			sequential = append(sequential, entry)
+		}
+	}
+
+	for i, entry := range sequential {
+		findings = append(findings, a.lintSequence(entry, sequential[i+1:])...)
+	}
+
+	return findings
+}
+
+func (a analyzer) lintPath(entry analyzedOp) []Finding {
+	var findings []Finding
+
+	tokens := entry.path.Tokens()
+	path := entry.path.String()
+
+	optional := false
+
+	for i, token := range tokens {
+		isLast := i == len(tokens)-1
+
+		switch typedToken := token.(type) {
+		case KeyToken:
+			if typedToken.Key == "-" && !isLast {
+				msg := fmt.Sprintf("Expected after last index token to be last in path '%s' (treated as map key '-')", path)
+				findings = append(findings, Finding{entry.index, FindingWarning, FindingAfterLastIndexNotLast, path, msg})
+			}
+			optional = optional || typedToken.Optional
+
+		case AfterLastIndexToken:
+			if !isLast {
+				msg := fmt.Sprintf("Expected after last index token to be last in path '%s'", path)
+				findings = append(findings, Finding{entry.index, FindingWarning, FindingAfterLastIndexNotLast, path, msg})
+			}
+
+		case IndexToken:
+			// missing values are removed as is hence index is not expected to be found
+			if _, ok := entry.op.(RemoveOp); optional && !ok {
+				msg := fmt.Sprintf("Expected array index '%d' following optional token in path '%s' to be "+
+					"optional but array items cannot be created by index", typedToken.Index, path)
+				findings = append(findings, Finding{entry.index, FindingWarning, FindingNonOptionalAfterOptional, path, msg})
+			}
+
+		case MatchingIndexToken:
+			optional = optional || typedToken.Optional
+
+		case MatchingValueToken:
+			optional = optional || typedToken.Optional
+		}
+	}
+
+	if testOp, ok := entry.op.(TestOp); ok && optional && testOp.comparesValue() {
+		msg := fmt.Sprintf("Expected path '%s' of value test to not be optional since missing values are found as null", path)
+		findings = append(findings, Finding{entry.index, FindingWarning, FindingOptionalTestValue, path, msg})
+	}
+
+	return findings
+}
+
+// lintSequence compares operation with the first later operation that touches the same location
+func (a analyzer) lintSequence(entry analyzedOp, later []analyzedOp) []Finding {
+	path := entry.path.String()
+	segs := a.segments(entry.path)
+
+	for _, next := range later {
+		nextSegs := a.segments(next.path)
+
+		if !a.hasPrefix(segs, nextSegs) && !a.hasPrefix(nextSegs, segs) {
+			if a.mayAlias(entry.path, next.path) {
+				return nil
+			}
+			continue
+		}
+
+		same := a.hasPrefix(segs, nextSegs) && len(segs) == len(nextSegs)
+
+		switch entry.op.(type) {
+		case ReplaceOp, MergeOp, StrategicMergeOp:
+			if a.adds(entry.path) {
+				return nil
+			}
+
+			_, nextRemoves := next.op.(RemoveOp)
+			_, nextReplaces := next.op.(ReplaceOp)
+
+			// later operation replaces or removes location itself or one of its parents
+			if (nextRemoves || (nextReplaces && !a.adds(next.path))) && a.hasPrefix(segs, nextSegs) {
+				msg := fmt.Sprintf("Expected value set at '%s' to be used but it's overwritten by operation [%d]", path, next.index)
+				return []Finding{{entry.index, FindingWarning, FindingShadowed, path, msg}}
+			}
+
+		case RemoveOp:
+			if _, ok := next.op.(ReplaceOp); ok && same && !a.adds(next.path) {
+				msg := fmt.Sprintf("Expected '%s' to not be removed since it's replaced by operation [%d]", path, next.index)
+				return []Finding{{entry.index, FindingWarning, FindingRemoveThenReplace, path, msg}}
+			}
+
+			if _, ok := next.op.(RemoveOp); ok && same && !a.indexed(entry.path) {
+				msg := fmt.Sprintf("Expected path '%s' to be removed once but it's also removed by operation [%d]", path, next.index)
+				return []Finding{{entry.index, FindingWarning, FindingDuplicatePath, path, msg}}
+			}
+
+		case TestOp:
+			if _, ok := next.op.(TestOp); ok && same && !a.indexed(entry.path) {
+				msg := fmt.Sprintf("Expected path '%s' to be tested once but it's also tested by operation [%d]", path, next.index)
+				return []Finding{{entry.index, FindingWarning, FindingDuplicatePath, path, msg}}
+			}
+
+			// tests do not modify document so later operations still need to be checked
+			if _, ok := next.op.(TestOp); ok {
+				continue
+			}
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// adds checks whether operation appends or inserts an array item
+func (analyzer) adds(ptr Pointer) bool {
+	tokens := ptr.Tokens()
+
+	var modifiers []Modifier
+
+	switch typedToken := tokens[len(tokens)-1].(type) {
+	case AfterLastIndexToken:
+		return true
+	case IndexToken:
+		modifiers = typedToken.Modifiers
+	case MatchingIndexToken:
+		modifiers = typedToken.Modifiers
+	case MatchingValueToken:
+		modifiers = typedToken.Modifiers
+	}
+
+	for _, modifier := range modifiers {
+		switch modifier.(type) {
+		case BeforeModifier, AfterModifier:
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexed checks whether path refers to an array item by index
+// since using same index twice may refer to different items
+func (analyzer) indexed(ptr Pointer) bool {
+	tokens := ptr.Tokens()
+	_, ok := tokens[len(tokens)-1].(IndexToken)
+	return ok
+}
+
+// mayAlias checks whether paths that differ as strings could still refer to the same location
+// (ex: '/ig/0/x' and '/ig/name=api/x', or '/ig/-1' and '/ig/2')
+func (a analyzer) mayAlias(left, right Pointer) bool {
+	leftSegs, rightSegs := a.segments(left), a.segments(right)
+	leftTokens, rightTokens := left.Tokens()[1:], right.Tokens()[1:]
+
+	for i := 0; i < len(leftSegs) && i < len(rightSegs); i++ {
+		if leftSegs[i] == rightSegs[i] {
+			continue
+		}
+
+		// different map keys never refer to the same location
+		_, leftKey := leftTokens[i].(KeyToken)
+		_, rightKey := rightTokens[i].(KeyToken)
+
+		return !leftKey || !rightKey
+	}
+
+	return false
+}
+
+// segments returns pointer pieces ignoring optionality
+func (analyzer) segments(ptr Pointer) []string {
+	var tokens []Token
+
+	for _, token := range ptr.Tokens() {
+		switch typedToken := token.(type) {
+		case KeyToken:
+			typedToken.Optional = false
+			token = typedToken
+		case MatchingIndexToken:
+			typedToken.Optional = false
+			token = typedToken
+		case MatchingValueToken:
+			typedToken.Optional = false
+			token = typedToken
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return strings.Split(NewPointer(tokens).String(), "/")[1:]
+}
+
+func (analyzer) hasPrefix(segs, prefix []string) bool {
+	if len(prefix) > len(segs) {
+		return false
+	}
+
+	for i, seg := range prefix {
+		if segs[i] != seg {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (analyzer) sort(findings []Finding) []Finding {
+	// keep findings of the same operation in order of discovery
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Index < findings[j].Index })
+	return findings
+}
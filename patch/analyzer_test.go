@@ -0,0 +1,252 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("AnalyzeOpDefinitions", func() {
+	analyze := func(str string) []Finding {
+		var opDefs []OpDefinition
+		Expect(yaml.Unmarshal([]byte(str), &opDefs)).To(Succeed())
+		return AnalyzeOpDefinitions(opDefs)
+	}
+
+	It("returns no findings for valid operations", func() {
+		findings := analyze(`
+- type: test
+  path: /name
+  value: dep
+- type: replace
+  path: /instance_groups/name=api/instances
+  value: 2
+- type: replace
+  path: /instance_groups/name=api/azs/-
+  value: z2
+- type: replace
+  path: /instance_groups/name=api/azs/-
+  value: z3
+- type: remove
+  path: /instance_groups/name=api/jobs/0
+- type: remove
+  path: /instance_groups/name=api/jobs/0
+`)
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reports invalid operations using parser checks", func() {
+		findings := analyze(`
+- type: replace
+  path: /a
+- type: unknown
+- type: remove
+  path: /a/b:next
+- type: group
+  path: /a
+  ops:
+  - type: remove
+    path: /b:prev
+`)
+		Expect(findings).To(Equal([]Finding{
+			{
+				Index:    0,
+				Severity: FindingError,
+				Code:     FindingInvalidOperation,
+				Path:     "/a",
+				Message:  "Replace operation: Missing value",
+			},
+			{
+				Index:    1,
+				Severity: FindingError,
+				Code:     FindingInvalidOperation,
+				Message:  "Unknown operation type 'unknown'",
+			},
+			{
+				Index:    2,
+				Severity: FindingError,
+				Code:     FindingKeyModifiers,
+				Path:     "/a/b:next",
+				Message:  "Expected not to find modifiers on key token 'b:next' in path '/a/b:next'",
+			},
+			{
+				Index:    3,
+				Severity: FindingError,
+				Code:     FindingKeyModifiers,
+				Path:     "/b:prev",
+				Message:  "Expected not to find modifiers on key token 'b:prev' in path '/b:prev'",
+			},
+		}))
+
+		Expect(findings[0].String()).To(Equal("Operation [0] error invalid-operation: Replace operation: Missing value"))
+	})
+
+	It("analyzes valid operations keeping their indexes", func() {
+		findings := analyze(`
+- type: replace
+  path: /a
+- type: replace
+  path: /b
+  value: 1
+- type: remove
+  path: /b
+`)
+		Expect(findings).To(HaveLen(2))
+		Expect(findings[0].Code).To(Equal(FindingInvalidOperation))
+		Expect(findings[1]).To(Equal(Finding{
+			Index:    1,
+			Severity: FindingWarning,
+			Code:     FindingShadowed,
+			Path:     "/b",
+			Message:  "Expected value set at '/b' to be used but it's overwritten by operation [2]",
+		}))
+	})
+})
+
+var _ = Describe("AnalyzeOps", func() {
+	codes := func(findings []Finding) []string {
+		var result []string
+		for _, f := range findings {
+			result = append(result, f.Code)
+		}
+		return result
+	}
+
+	It("reports values overwritten by later operations", func() {
+		findings := AnalyzeOps(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a/b"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/c"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/a?"), Value: 2},
+			ReplaceOp{Path: MustNewPointerFromString("/c/d"), Value: 2},
+			MergeOp{Path: MustNewPointerFromString("/e"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/e")},
+		})
+
+		Expect(findings).To(Equal([]Finding{
+			{
+				Index:    0,
+				Severity: FindingWarning,
+				Code:     FindingShadowed,
+				Path:     "/a/b",
+				Message:  "Expected value set at '/a/b' to be used but it's overwritten by operation [2]",
+			},
+			{
+				Index:    4,
+				Severity: FindingWarning,
+				Code:     FindingShadowed,
+				Path:     "/e",
+				Message:  "Expected value set at '/e' to be used but it's overwritten by operation [5]",
+			},
+		}))
+	})
+
+	It("does not report values that are used before being overwritten", func() {
+		findings := AnalyzeOps(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a"), Value: 1},
+			TestOp{Path: MustNewPointerFromString("/a"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/a"), Value: 2},
+			ReplaceOp{Path: MustNewPointerFromString("/b"), Value: 1},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/c"), Absent: true},
+				Then: Ops{ReplaceOp{Path: MustNewPointerFromString("/b"), Value: 2}},
+			},
+		})
+
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reports removals of values that are replaced afterwards", func() {
+		findings := AnalyzeOps(Ops{
+			RemoveOp{Path: MustNewPointerFromString("/a")},
+			ReplaceOp{Path: MustNewPointerFromString("/a?"), Value: 1},
+		})
+
+		Expect(findings).To(Equal([]Finding{{
+			Index:    0,
+			Severity: FindingWarning,
+			Code:     FindingRemoveThenReplace,
+			Path:     "/a",
+			Message:  "Expected '/a' to not be removed since it's replaced by operation [1]",
+		}}))
+	})
+
+	It("reports duplicate removals and tests", func() {
+		findings := AnalyzeOps(Ops{
+			TestOp{Path: MustNewPointerFromString("/a"), Value: 1},
+			TestOp{Path: MustNewPointerFromString("/a"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/items/name=x")},
+			RemoveOp{Path: MustNewPointerFromString("/items/name=x?")},
+		})
+
+		Expect(codes(findings)).To(Equal([]string{FindingDuplicatePath, FindingDuplicatePath}))
+		Expect(findings[0].Message).To(Equal("Expected path '/a' to be tested once but it's also tested by operation [1]"))
+		Expect(findings[1].Message).To(Equal("Expected path '/items/name=x' to be removed once but it's also removed by operation [3]"))
+	})
+
+	It("reports after last index tokens that are not last", func() {
+		findings := AnalyzeOps(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a/-/b"), Value: 1},
+		})
+
+		Expect(findings).To(Equal([]Finding{{
+			Index:    0,
+			Severity: FindingWarning,
+			Code:     FindingAfterLastIndexNotLast,
+			Path:     "/a/-/b",
+			Message:  "Expected after last index token to be last in path '/a/-/b' (treated as map key '-')",
+		}}))
+	})
+
+	It("reports array indexes and value tests following optional tokens", func() {
+		findings := AnalyzeOps(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a?/0"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/b?/0")},
+			TestOp{Path: MustNewPointerFromString("/c?/d"), Value: 1},
+			TestOp{Path: MustNewPointerFromString("/e?"), Absent: true},
+		})
+
+		Expect(findings).To(Equal([]Finding{
+			{
+				Index:    0,
+				Severity: FindingWarning,
+				Code:     FindingNonOptionalAfterOptional,
+				Path:     "/a?/0",
+				Message:  "Expected array index '0' following optional token in path '/a?/0' to be optional but array items cannot be created by index",
+			},
+			{
+				Index:    2,
+				Severity: FindingWarning,
+				Code:     FindingOptionalTestValue,
+				Path:     "/c?/d",
+				Message:  "Expected path '/c?/d' of value test to not be optional since missing values are found as null",
+			},
+		}))
+	})
+
+	It("analyzes nested operations with indexes of top level operations", func() {
+		findings := AnalyzeOps(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/x"), Value: 1},
+			DescriptiveOp{
+				Op: GroupOp{
+					Path: MustNewPointerFromString("/ig/name=api"),
+					Ops: Ops{
+						ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 1},
+						ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 2},
+					},
+				},
+				ErrorMsg: "desc",
+			},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/y"), Absent: true},
+				Then: Ops{ReplaceOp{Path: MustNewPointerFromString("/a?/-/b"), Value: 1}},
+			},
+		})
+
+		Expect(codes(findings)).To(Equal([]string{FindingShadowed, FindingAfterLastIndexNotLast}))
+		Expect(findings[0].Index).To(Equal(1))
+		Expect(findings[0].Path).To(Equal("/ig/name=api/instances"))
+		Expect(findings[1].Index).To(Equal(2))
+	})
+})
@@ -1,7 +1,6 @@
 package patch
 
 import (
-	"fmt"
 	"reflect"
 )
 
@@ -22,12 +21,12 @@ func (i ArrayIndex) Concrete() (int, error) {
 		case NextModifier:
 			result += 1
 		default:
-			return 0, fmt.Errorf("Expected to find one of the following modifiers: 'prev', 'next', but found modifier '%T'", modifier)
+			return 0, OpUnexpectedModifierErr{Modifier: modifier, Path: i.Path}
 		}
 	}
 
 	if result >= i.Array.Len() || (-result)-1 >= i.Array.Len() {
-		return 0, OpMissingIndexErr{result, i.Array, i.Path}
+		return 0, OpMissingIndexErr{Idx: result, Obj: i.Array, Path: i.Path}
 	}
 
 	if result < 0 {
@@ -41,24 +41,24 @@ var _ = Describe("ArrayIndex", func() {
 		It("does not work with empty arrays", func() {
 			idx := ArrayIndex{Index: 0, Modifiers: nil, Array: reflect.ValueOf([]interface{}{}), Path: dummyPath}
 			_, err := idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '0' but found array of length '0' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '0' but found array of length '0' for path '' (array is empty)`))
 
 			p := PrevModifier{}
 			n := NextModifier{}
 
 			idx = ArrayIndex{Index: 0, Modifiers: []Modifier{p, n}, Array: reflect.ValueOf([]interface{}{}), Path: dummyPath}
 			_, err = idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '0' but found array of length '0' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '0' but found array of length '0' for path '' (array is empty)`))
 		})
 
 		It("does not work with index out of bounds", func() {
 			idx := ArrayIndex{Index: 3, Modifiers: nil, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			_, err := idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '3' but found array of length '3' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '3' but found array of length '3' for path '' (valid indices are 0 to 2 or -3 to -1)`))
 
 			idx = ArrayIndex{Index: -4, Modifiers: nil, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			_, err = idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '-4' but found array of length '3' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '-4' but found array of length '3' for path '' (valid indices are 0 to 2 or -3 to -1)`))
 		})
 
 		It("returns previous item when previous modifier is used", func() {
@@ -75,11 +75,11 @@ var _ = Describe("ArrayIndex", func() {
 
 			idx = ArrayIndex{Index: 0, Modifiers: []Modifier{p, p, p, p}, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			_, err := idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '-4' but found array of length '3' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '-4' but found array of length '3' for path '' (valid indices are 0 to 2 or -3 to -1)`))
 
 			idx = ArrayIndex{Index: 0, Modifiers: []Modifier{p, p, p, p, p}, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			_, err = idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '-5' but found array of length '3' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '-5' but found array of length '3' for path '' (valid indices are 0 to 2 or -3 to -1)`))
 
 			idx = ArrayIndex{Index: 2, Modifiers: []Modifier{p, p}, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			Expect(idx.Concrete()).To(Equal(0))
@@ -96,11 +96,11 @@ var _ = Describe("ArrayIndex", func() {
 
 			idx = ArrayIndex{Index: 0, Modifiers: []Modifier{n, n, n}, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			_, err := idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '3' but found array of length '3' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '3' but found array of length '3' for path '' (valid indices are 0 to 2 or -3 to -1)`))
 
 			idx = ArrayIndex{Index: 0, Modifiers: []Modifier{n, n, n, n}, Array: reflect.ValueOf([]interface{}{1, 2, 3}), Path: dummyPath}
 			_, err = idx.Concrete()
-			Expect(err).To(MatchError(`Expected to find array index '4' but found array of length '3' for path ''`))
+			Expect(err).To(MatchError(`Expected to find array index '4' but found array of length '3' for path '' (valid indices are 0 to 2 or -3 to -1)`))
 		})
 
 		It("works with multiple previous and next modifiers", func() {
@@ -1,7 +1,6 @@
 package patch
 
 import (
-	"fmt"
 	"reflect"
 )
 
@@ -25,12 +24,10 @@ func (i ArrayInsertion) Concrete() (ArrayInsertionIndex, error) {
 
 	for _, modifier := range i.Modifiers {
 		if before {
-			return ArrayInsertionIndex{}, fmt.Errorf(
-				"Expected to not find any modifiers after 'before' modifier, but found modifier '%T'", modifier)
+			return ArrayInsertionIndex{}, OpUnexpectedModifierErr{Modifier: modifier, Path: i.Path, After: "before"}
 		}
 		if after {
-			return ArrayInsertionIndex{}, fmt.Errorf(
-				"Expected to not find any modifiers after 'after' modifier, but found modifier '%T'", modifier)
+			return ArrayInsertionIndex{}, OpUnexpectedModifierErr{Modifier: modifier, Path: i.Path, After: "after"}
 		}
 
 		switch modifier.(type) {
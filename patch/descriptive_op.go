@@ -1,7 +1,7 @@
 package patch
 
 import (
-	"fmt"
+	"context"
 )
 
 type DescriptiveOp struct {
@@ -10,9 +10,17 @@ type DescriptiveOp struct {
 }
 
 func (op DescriptiveOp) Apply(doc interface{}) (interface{}, error) {
-	doc, err := op.Op.Apply(doc)
+	return op.ApplyContext(context.Background(), doc)
+}
+
+func (op DescriptiveOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	doc, err := applyContext(ctx, op.Op, doc)
 	if err != nil {
-		return nil, fmt.Errorf("Error '%s': %s", op.ErrorMsg, err.Error())
+		// keep cancellation errors intact
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, DescriptiveOpErr{ErrorMsg: op.ErrorMsg, Err: err}
 	}
 	return doc, nil
 }
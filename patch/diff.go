@@ -1,8 +1,8 @@
 package patch
 
 import (
+	"context"
 	"fmt"
-	"reflect"
 	"sort"
 
 	"gopkg.in/yaml.v2"
@@ -12,12 +12,41 @@ type Diff struct {
 	Left      interface{}
 	Right     interface{}
 	Unchecked bool
+	Equality  Equality // also used by generated test operations
+
+	// Ignore and Include are patterns matched against paths found in documents;
+	// besides regular tokens, '*' matches any single key or index and '**' matches any number of them
+	Ignore  []Pointer // skipped paths (including their children)
+	Include []Pointer // if specified, only matching paths (including their children) are compared
 }
 
 func (d Diff) Calculate() Ops {
-	ops := d.calculate(d.Left, d.Right, []Token{RootToken{}})
+	ops, _ := d.CalculateContext(context.Background())
+	return ops
+}
+
+// CalculateContext checks for cancellation while traversing documents
+func (d Diff) CalculateContext(ctx context.Context) (Ops, error) {
+	ops, _, err := d.CalculateWithIgnoredContext(ctx)
+	return ops, err
+}
+
+// CalculateWithIgnored additionally returns ignored paths that differ between documents
+func (d Diff) CalculateWithIgnored() (Ops, []Pointer) {
+	ops, ignored, _ := d.CalculateWithIgnoredContext(context.Background())
+	return ops, ignored
+}
+
+func (d Diff) CalculateWithIgnoredContext(ctx context.Context) (Ops, []Pointer, error) {
+	ignored := []Pointer{}
+
+	ops, err := d.calculateRoot(ctx, d.rootScope(&ignored))
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if !d.Unchecked {
-		return ops
+		return ops, ignored, nil
 	}
 
 	newOps := []Op{}
@@ -26,12 +55,25 @@ func (d Diff) Calculate() Ops {
 			newOps = append(newOps, op)
 		}
 	}
-	return newOps
+	return newOps, ignored, nil
+}
+
+func (d Diff) calculateRoot(ctx context.Context, scope diffScope) ([]Op, error) {
+	scope, skip := d.filter(scope, diffNode{RootToken{}, d.Left, d.Right, true, true})
+	if skip {
+		return []Op{}, nil
+	}
+
+	return d.calculate(ctx, d.Left, d.Right, scope)
 }
 
-func (d Diff) calculate(left, right interface{}, tokens []Token) []Op {
+func (d Diff) calculate(ctx context.Context, left, right interface{}, scope diffScope) ([]Op, error) {
 	switch typedLeft := left.(type) {
 	case map[interface{}]interface{}:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		if typedRight, ok := right.(map[interface{}]interface{}); ok {
 			ops := []Op{}
 			var allKeys []interface{}
@@ -49,80 +91,138 @@ func (d Diff) calculate(left, right interface{}, tokens []Token) []Op {
 				return string(iBs) < string(jBs)
 			})
 			for _, k := range allKeys {
-				newTokens := append([]Token{}, tokens...)
-				if leftVal, found := typedLeft[k]; found {
-					newTokens = append(newTokens, KeyToken{Key: fmt.Sprintf("%s", k)})
-					if rightVal, found := typedRight[k]; found {
-						ops = append(ops, d.calculate(leftVal, rightVal, newTokens)...)
-					} else { // remove existing
+				key := fmt.Sprintf("%s", k)
+				leftVal, leftFound := typedLeft[k]
+				rightVal, rightFound := typedRight[k]
+
+				childScope, skip := d.child(scope, diffNode{KeyToken{Key: key}, leftVal, rightVal, leftFound, rightFound})
+				if skip {
+					continue
+				}
+
+				switch {
+				case leftFound && rightFound:
+					childOps, err := d.calculate(ctx, leftVal, rightVal, childScope)
+					if err != nil {
+						return nil, err
+					}
+					ops = append(ops, childOps...)
+
+				case !childScope.included: // look for included paths within value found on one side only
+					if !leftFound {
+						leftVal = emptyLike(rightVal)
+					}
+					if !rightFound {
+						rightVal = emptyLike(leftVal)
+					}
+					childOps, err := d.calculate(ctx, leftVal, rightVal, childScope)
+					if err != nil {
+						return nil, err
+					}
+					ops = append(ops, childOps...)
+
+				case leftFound: // remove existing
+					if !d.Equality.absent(leftVal) {
 						ops = append(ops,
-							TestOp{Path: NewPointer(newTokens), Value: leftVal},
-							RemoveOp{Path: NewPointer(newTokens)},
+							TestOp{Path: NewPointer(childScope.tokens), Value: leftVal, Equality: d.Equality},
+							RemoveOp{Path: NewPointer(childScope.tokens)},
 						)
+						childScope.record(diffChange{kind: diffChangeRemoved, path: NewPointer(childScope.tokens), old: leftVal})
+					}
+
+				default: // add new
+					if !d.Equality.absent(rightVal) {
+						testTokens := childScope.creatingTokens()
+						testTokens[len(testTokens)-1] = KeyToken{Key: key}
+						ops = append(ops,
+							TestOp{Path: NewPointer(testTokens), Absent: true, Equality: d.Equality},
+							ReplaceOp{Path: NewPointer(childScope.creatingTokens()), Value: rightVal},
+						)
+						childScope.record(diffChange{kind: diffChangeAdded, path: NewPointer(childScope.tokens), new: rightVal})
 					}
-				} else { // add new
-					testOpTokens := append([]Token{}, newTokens...)
-					testOpTokens = append(testOpTokens, KeyToken{Key: fmt.Sprintf("%s", k)})
-					newTokens = append(newTokens, KeyToken{Key: fmt.Sprintf("%s", k), Optional: true})
-					ops = append(ops,
-						TestOp{Path: NewPointer(testOpTokens), Absent: true},
-						ReplaceOp{Path: NewPointer(newTokens), Value: typedRight[k]},
-					)
 				}
 			}
-			return ops
-		}
-		return []Op{
-			TestOp{Path: NewPointer(tokens), Value: left},
-			ReplaceOp{Path: NewPointer(tokens), Value: right},
+			return ops, nil
 		}
+		return d.replace(left, right, scope), nil
 
 	case []interface{}:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		if typedRight, ok := right.([]interface{}); ok {
 			ops := []Op{}
 			actualIndex := 0
 			for i := 0; i < max(len(typedLeft), len(typedRight)); i++ {
-				newTokens := append([]Token{}, tokens...)
+				node := diffNode{token: IndexToken{Index: i}}
+				if i < len(typedLeft) {
+					node.left, node.leftFound = typedLeft[i], true
+				}
+				if i < len(typedRight) {
+					node.right, node.rightFound = typedRight[i], true
+				}
+
+				childScope, skip := d.child(scope, node)
+
+				newTokens := append([]Token{}, scope.tokens...)
 				switch {
 				case i >= len(typedRight): // remove existing
+					if skip || !childScope.included {
+						actualIndex++ // item is kept
+						continue
+					}
 					newTokens = append(newTokens, IndexToken{Index: actualIndex})
 					ops = append(ops,
-						TestOp{Path: NewPointer(newTokens), Value: typedLeft[i]}, // capture actual value at index
+						TestOp{Path: NewPointer(newTokens), Value: typedLeft[i], Equality: d.Equality}, // capture actual value at index
 						RemoveOp{Path: NewPointer(newTokens)},
 					)
+					childScope.record(diffChange{kind: diffChangeRemoved, path: NewPointer(childScope.tokens), old: typedLeft[i]}) // original index
 					// keep actualIndex the same
 				case i >= len(typedLeft): // add new
+					if skip || !childScope.included {
+						continue
+					}
 					testOpTokens := append([]Token{}, newTokens...)
 					testOpTokens = append(testOpTokens, IndexToken{Index: i}) // use actual index
 					newTokens = append(newTokens, AfterLastIndexToken{})
 					ops = append(ops,
-						TestOp{Path: NewPointer(testOpTokens), Absent: true},
+						TestOp{Path: NewPointer(testOpTokens), Absent: true, Equality: d.Equality},
 						ReplaceOp{Path: NewPointer(newTokens), Value: typedRight[i]},
 					)
+					childScope.record(diffChange{kind: diffChangeAdded, path: NewPointer(childScope.tokens), new: typedRight[i]})
 					actualIndex++
 				default:
-					newTokens = append(newTokens, IndexToken{Index: actualIndex})
-					ops = append(ops, d.calculate(typedLeft[i], typedRight[i], newTokens)...)
+					if !skip {
+						childOps, err := d.calculate(ctx, typedLeft[i], typedRight[i], childScope)
+						if err != nil {
+							return nil, err
+						}
+						ops = append(ops, childOps...)
+					}
 					actualIndex++
 				}
 			}
-			return ops
-		}
-		return []Op{
-			TestOp{Path: NewPointer(tokens), Value: left},
-			ReplaceOp{Path: NewPointer(tokens), Value: right},
+			return ops, nil
 		}
+		return d.replace(left, right, scope), nil
 
 	default:
-		if !reflect.DeepEqual(left, right) {
-			return []Op{
-				TestOp{Path: NewPointer(tokens), Value: left},
-				ReplaceOp{Path: NewPointer(tokens), Value: right},
-			}
-		}
+		return d.replace(left, right, scope), nil
+	}
+}
+
+func (d Diff) replace(left, right interface{}, scope diffScope) []Op {
+	if !scope.included || d.Equality.Equal(left, right) {
+		return []Op{}
 	}
 
-	return []Op{}
+	scope.record(diffChange{kind: diffChangeChanged, path: NewPointer(scope.tokens), old: left, new: right})
+
+	return []Op{
+		TestOp{Path: NewPointer(scope.tokens), Value: left, Equality: d.Equality},
+		ReplaceOp{Path: NewPointer(scope.tokens), Value: right},
+	}
 }
 
 func max(a, b int) int {
@@ -0,0 +1,170 @@
+package patch
+
+import (
+	"reflect"
+)
+
+// diffScope tracks location within documents to match ignore and include patterns
+type diffScope struct {
+	tokens   []Token
+	nodes    []diffNode
+	included bool // include pattern matched current location or one of its parents
+	ignored  *[]Pointer
+	changes  *[]diffChange // optionally collects changes for reports
+}
+
+func (d Diff) rootScope(ignored *[]Pointer) diffScope {
+	return diffScope{
+		tokens:   []Token{RootToken{}},
+		included: len(d.Include) == 0,
+		ignored:  ignored,
+	}
+}
+
+func (s diffScope) record(change diffChange) {
+	if s.changes != nil {
+		*s.changes = append(*s.changes, change)
+	}
+}
+
+// creatingTokens marks keys missing in left document as optional so that they are created
+func (s diffScope) creatingTokens() []Token {
+	tokens := append([]Token{}, s.tokens...)
+
+	for i, node := range s.nodes {
+		if typedToken, ok := tokens[i+1].(KeyToken); ok && !node.leftFound {
+			tokens[i+1] = KeyToken{Key: typedToken.Key, Optional: true}
+		}
+	}
+
+	return tokens
+}
+
+type diffNode struct {
+	token      Token // key or index of the value
+	left       interface{}
+	right      interface{}
+	leftFound  bool
+	rightFound bool
+}
+
+func (d Diff) child(scope diffScope, node diffNode) (diffScope, bool) {
+	scope.tokens = append(append([]Token{}, scope.tokens...), node.token)
+	scope.nodes = append(append([]diffNode{}, scope.nodes...), node)
+
+	return d.filter(scope, node)
+}
+
+// filter returns true if location should be skipped
+func (d Diff) filter(scope diffScope, node diffNode) (diffScope, bool) {
+	for _, pattern := range d.Ignore {
+		if matched, _ := matchPattern(pattern.Tokens()[1:], scope.nodes); matched {
+			if d.differs(node) {
+				*scope.ignored = append(*scope.ignored, NewPointer(scope.tokens))
+			}
+			return scope, true
+		}
+	}
+
+	if scope.included {
+		return scope, false
+	}
+
+	var partial bool
+
+	for _, pattern := range d.Include {
+		matched, couldMatch := matchPattern(pattern.Tokens()[1:], scope.nodes)
+		if matched {
+			scope.included = true
+			return scope, false
+		}
+		partial = partial || couldMatch
+	}
+
+	// keep looking for locations matching include patterns within children
+	return scope, !partial
+}
+
+func (d Diff) differs(node diffNode) bool {
+	switch {
+	case node.leftFound && node.rightFound:
+		return !d.Equality.Equal(node.left, node.right)
+	case node.leftFound:
+		return !d.Equality.absent(node.left)
+	case node.rightFound:
+		return !d.Equality.absent(node.right)
+	default:
+		return false
+	}
+}
+
+// matchPattern returns true if pattern matches location (or one of its parents);
+// partial is true if pattern could match one of location's children
+func matchPattern(pattern []Token, nodes []diffNode) (matched bool, partial bool) {
+	if len(pattern) == 0 {
+		return true, false
+	}
+
+	if len(nodes) == 0 {
+		return false, true
+	}
+
+	if typedToken, ok := pattern[0].(KeyToken); ok && typedToken.Key == "**" {
+		matchedHere, partialHere := matchPattern(pattern[1:], nodes)
+		matchedBelow, partialBelow := matchPattern(pattern, nodes[1:])
+		return matchedHere || matchedBelow, partialHere || partialBelow
+	}
+
+	if !matchPatternToken(pattern[0], nodes[0]) {
+		return false, false
+	}
+
+	return matchPattern(pattern[1:], nodes[1:])
+}
+
+func matchPatternToken(token Token, node diffNode) bool {
+	switch typedToken := token.(type) {
+	case KeyToken:
+		if typedToken.Key == "*" {
+			return true
+		}
+		nodeToken, ok := node.token.(KeyToken)
+		return ok && nodeToken.Key == typedToken.Key
+
+	case IndexToken:
+		nodeToken, ok := node.token.(IndexToken)
+		return ok && nodeToken.Index == typedToken.Index
+
+	case MatchingIndexToken:
+		if nodeToken, ok := node.token.(MatchingIndexToken); ok {
+			return nodeToken.Key == typedToken.Key && nodeToken.Value == typedToken.Value
+		}
+		return matchPatternItem(node, func(items reflect.Value) []int {
+			return findMapIndices(items, typedToken.Key, typedToken.Value)
+		})
+
+	case MatchingValueToken:
+		if nodeToken, ok := node.token.(MatchingValueToken); ok {
+			return nodeToken.Value == typedToken.Value
+		}
+		return matchPatternItem(node, func(items reflect.Value) []int {
+			return findValueIndices(items, typedToken.Value)
+		})
+
+	default:
+		return false
+	}
+}
+
+// matchPatternItem checks array item found in either document the same way pointers find items
+func matchPatternItem(node diffNode, find func(reflect.Value) []int) bool {
+	if _, ok := node.token.(IndexToken); !ok {
+		return false
+	}
+
+	if node.leftFound && len(find(reflect.ValueOf([]interface{}{node.left}))) > 0 {
+		return true
+	}
+
+	return node.rightFound && len(find(reflect.ValueOf([]interface{}{node.right}))) > 0
+}
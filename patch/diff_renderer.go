@@ -0,0 +1,221 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	diffChangeAdded   = "+"
+	diffChangeRemoved = "-"
+	diffChangeChanged = "~"
+)
+
+var diffColors = map[string]string{
+	diffChangeAdded:   "\x1b[32m",
+	diffChangeRemoved: "\x1b[31m",
+	diffChangeChanged: "\x1b[33m",
+}
+
+// DiffRenderer renders differences between documents for people (e.g. in terminals or CI comments)
+type DiffRenderer struct {
+	Color      bool // uses ANSI colors
+	GroupDepth int  // groups changes by leading path tokens (ex: 2 groups by instance groups)
+
+	// Redact returns true if value (or any nested value) should be hidden
+	Redact func(path Pointer, val interface{}) bool
+}
+
+type diffChange struct {
+	kind string
+	path Pointer // concrete path within left document (or right document for added values)
+	old  interface{}
+	new  interface{}
+}
+
+// Render returns changes made to the left document as lines prefixed with '+', '-' or '~';
+// array items are referred to by their names if possible (ex: '/instance_groups/name=api')
+func (r DiffRenderer) Render(d Diff) string {
+	var groups []string
+	var lines []string
+
+	groupLines := map[string][]string{}
+
+	for _, change := range d.changes() {
+		path := r.namedPath(d, change)
+		tokens := path.Tokens()
+
+		if r.GroupDepth <= 0 || len(tokens) <= r.GroupDepth+1 {
+			lines = append(lines, r.renderChange(change, path, path, "")...)
+			continue
+		}
+
+		group := NewPointer(tokens[:r.GroupDepth+1])
+		relPath := NewPointer(append([]Token{RootToken{}}, tokens[r.GroupDepth+1:]...))
+
+		if _, found := groupLines[group.String()]; !found {
+			groups = append(groups, group.String())
+		}
+
+		groupLines[group.String()] = append(groupLines[group.String()], r.renderChange(change, path, relPath, "  ")...)
+	}
+
+	for _, group := range groups {
+		lines = append(lines, r.colorize("\x1b[1m", group+":"))
+		lines = append(lines, groupLines[group]...)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (r DiffRenderer) renderChange(change diffChange, path, shownPath Pointer, indent string) []string {
+	color := diffColors[change.kind]
+	prefix := indent + change.kind + " " + shownPath.String() + ":"
+
+	oldLines := r.valueLines(path, change.old)
+	newLines := r.valueLines(path, change.new)
+
+	switch change.kind {
+	case diffChangeAdded:
+		return r.renderValue(color, prefix, indent, newLines)
+
+	case diffChangeRemoved:
+		return r.renderValue(color, prefix, indent, oldLines)
+
+	default:
+		if len(oldLines) == 1 && len(newLines) == 1 {
+			return []string{r.colorize(color, fmt.Sprintf("%s %s → %s", prefix, oldLines[0], newLines[0]))}
+		}
+
+		lines := []string{r.colorize(color, prefix)}
+		for _, line := range oldLines {
+			lines = append(lines, r.colorize(diffColors[diffChangeRemoved], indent+"    - "+line))
+		}
+		for _, line := range newLines {
+			lines = append(lines, r.colorize(diffColors[diffChangeAdded], indent+"    + "+line))
+		}
+		return lines
+	}
+}
+
+func (r DiffRenderer) renderValue(color, prefix, indent string, valLines []string) []string {
+	if len(valLines) == 1 {
+		return []string{r.colorize(color, prefix+" "+valLines[0])}
+	}
+
+	lines := []string{r.colorize(color, prefix)}
+	for _, line := range valLines {
+		lines = append(lines, r.colorize(color, indent+"    "+line))
+	}
+	return lines
+}
+
+func (r DiffRenderer) valueLines(path Pointer, val interface{}) []string {
+	bytes, err := yaml.Marshal(r.redact(path, val))
+	if err != nil {
+		return []string{fmt.Sprintf("%v", val)}
+	}
+
+	return strings.Split(strings.TrimSuffix(string(bytes), "\n"), "\n")
+}
+
+func (r DiffRenderer) redact(path Pointer, val interface{}) interface{} {
+	if r.Redact == nil {
+		return val
+	}
+
+	if r.Redact(path, val) {
+		return redactedValue
+	}
+
+	childPath := func(token Token) Pointer {
+		return NewPointer(append(append([]Token{}, path.Tokens()...), token))
+	}
+
+	switch typedVal := val.(type) {
+	case map[interface{}]interface{}:
+		result := map[interface{}]interface{}{}
+		for k, v := range typedVal {
+			result[k] = r.redact(childPath(KeyToken{Key: fmt.Sprintf("%v", k)}), v)
+		}
+		return result
+
+	case []interface{}:
+		result := []interface{}{}
+		for i, v := range typedVal {
+			result = append(result, r.redact(childPath(IndexToken{Index: i}), v))
+		}
+		return result
+
+	default:
+		return val
+	}
+}
+
+func (r DiffRenderer) colorize(color, line string) string {
+	if !r.Color {
+		return line
+	}
+	return color + line + "\x1b[0m"
+}
+
+// namedPath replaces indices of array items that have unique names with 'name=...' tokens
+func (DiffRenderer) namedPath(d Diff, change diffChange) Pointer {
+	doc := d.Left
+	if change.kind == diffChangeAdded {
+		doc = d.Right
+	}
+
+	tokens := change.path.Tokens()
+	named := []Token{RootToken{}}
+
+	for i, token := range tokens[1:] {
+		switch typedToken := token.(type) {
+		case KeyToken:
+			obj, ok := doc.(map[interface{}]interface{})
+			if !ok {
+				return NewPointer(append(named, tokens[i+1:]...))
+			}
+			doc = obj[typedToken.Key]
+
+		case IndexToken:
+			array, ok := doc.([]interface{})
+			if !ok || typedToken.Index >= len(array) {
+				return NewPointer(append(named, tokens[i+1:]...))
+			}
+
+			doc = array[typedToken.Index]
+
+			if item, ok := doc.(map[interface{}]interface{}); ok {
+				if name, ok := item["name"].(string); ok && len(findMapIndices(reflect.ValueOf(array), "name", name)) == 1 {
+					named = append(named, MatchingIndexToken{Key: "name", Value: name})
+					continue
+				}
+			}
+		}
+
+		named = append(named, token)
+	}
+
+	return NewPointer(named)
+}
+
+// changes lists differences between documents found by the diff
+func (d Diff) changes() []diffChange {
+	var changes []diffChange
+
+	scope := d.rootScope(&[]Pointer{})
+	scope.changes = &changes
+
+	d.calculateRoot(context.Background(), scope)
+
+	return changes
+}
@@ -0,0 +1,129 @@
+package patch_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("DiffRenderer.Render", func() {
+	left := map[interface{}]interface{}{
+		"name": "dep",
+		"instance_groups": []interface{}{
+			map[interface{}]interface{}{"name": "api", "instances": 1, "azs": []interface{}{"z1", "z2", "z3"}},
+			map[interface{}]interface{}{"name": "db", "instances": 1, "password": "old-secret"},
+		},
+		"releases": []interface{}{
+			map[interface{}]interface{}{"name": "uaa", "version": 1},
+		},
+	}
+	right := map[interface{}]interface{}{
+		"name": "dep2",
+		"instance_groups": []interface{}{
+			map[interface{}]interface{}{"name": "api", "instances": 2, "azs": []interface{}{"z1"}},
+			map[interface{}]interface{}{"name": "db", "instances": 1, "password": "new-secret"},
+			map[interface{}]interface{}{"name": "worker", "instances": 1},
+		},
+		"releases": []interface{}{"uaa"},
+	}
+
+	It("renders added, removed and changed values with array items referred to by name", func() {
+		out := DiffRenderer{}.Render(Diff{Left: left, Right: right})
+
+		Expect("\n" + out).To(Equal(`
+- /instance_groups/name=api/azs/1: z2
+- /instance_groups/name=api/azs/2: z3
+~ /instance_groups/name=api/instances: 1 → 2
+~ /instance_groups/name=db/password: old-secret → new-secret
++ /instance_groups/name=worker:
+    instances: 1
+    name: worker
+~ /name: dep → dep2
+~ /releases/name=uaa:
+    - name: uaa
+    - version: 1
+    + uaa
+`))
+	})
+
+	It("renders included values added within new maps", func() {
+		out := DiffRenderer{}.Render(Diff{
+			Left:    map[interface{}]interface{}{},
+			Right:   map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 1, "c": 2}},
+			Include: []Pointer{MustNewPointerFromString("/a/b")},
+		})
+
+		Expect(out).To(Equal("+ /a/b: 1\n"))
+	})
+
+	It("renders removed array items at their original indices", func() {
+		out := DiffRenderer{}.Render(Diff{
+			Left:   []interface{}{"a", "b", "c", "d"},
+			Right:  []interface{}{"a"},
+			Ignore: []Pointer{MustNewPointerFromString("/2")},
+		})
+
+		Expect(out).To(Equal("- /1: b\n- /3: d\n"))
+	})
+
+	It("groups changes by leading path tokens", func() {
+		out := DiffRenderer{GroupDepth: 2}.Render(Diff{Left: left, Right: right})
+
+		Expect("\n" + out).To(Equal(`
++ /instance_groups/name=worker:
+    instances: 1
+    name: worker
+~ /name: dep → dep2
+~ /releases/name=uaa:
+    - name: uaa
+    - version: 1
+    + uaa
+/instance_groups/name=api:
+  - /azs/1: z2
+  - /azs/2: z3
+  ~ /instances: 1 → 2
+/instance_groups/name=db:
+  ~ /password: old-secret → new-secret
+`))
+	})
+
+	It("redacts values (including nested ones)", func() {
+		renderer := DiffRenderer{
+			Redact: func(path Pointer, val interface{}) bool {
+				return strings.HasSuffix(path.String(), "password")
+			},
+		}
+
+		out := renderer.Render(Diff{
+			Left:  left,
+			Right: map[interface{}]interface{}{"creds": map[interface{}]interface{}{"user": "admin", "password": "secret"}},
+		})
+
+		Expect(out).To(ContainSubstring("+ /creds:\n    password: <redacted>\n    user: admin\n"))
+		Expect(out).To(ContainSubstring("- /instance_groups:\n"))
+		Expect(out).To(ContainSubstring("  password: <redacted>\n"))
+		Expect(out).ToNot(ContainSubstring("secret"))
+
+		out = renderer.Render(Diff{Left: left, Right: right})
+		Expect(out).To(ContainSubstring("~ /instance_groups/name=db/password: <redacted> → <redacted>\n"))
+	})
+
+	It("colors lines", func() {
+		out := DiffRenderer{Color: true, GroupDepth: 1}.Render(Diff{
+			Left:  map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 1, "c": 1}},
+			Right: map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 2, "d": 1}},
+		})
+
+		Expect(out).To(Equal("\x1b[1m/a:\x1b[0m\n" +
+			"\x1b[33m  ~ /b: 1 → 2\x1b[0m\n" +
+			"\x1b[31m  - /c: 1\x1b[0m\n" +
+			"\x1b[32m  + /d: 1\x1b[0m\n"))
+	})
+
+	It("returns empty string if there are no differences", func() {
+		Expect(DiffRenderer{}.Render(Diff{Left: left, Right: left})).To(BeEmpty())
+	})
+})
@@ -1,6 +1,8 @@
 package patch_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -243,3 +245,204 @@ var _ = Describe("Diff.Calculate", func() {
 		)
 	})
 })
+
+var _ = Describe("Diff.Calculate with equality", func() {
+	It("does not report semantically equal values", func() {
+		eq := Equality{NormalizeNumbers: true, NullAsAbsent: true, IgnoreEmpty: true}
+
+		left := map[interface{}]interface{}{
+			"a": 1,
+			"b": nil,
+			"c": []interface{}{uint64(2), 3.5},
+			"d": map[interface{}]interface{}{},
+		}
+		right := map[interface{}]interface{}{
+			"a": 1.0,
+			"c": []interface{}{2, 3.5},
+			"e": []interface{}{},
+		}
+
+		Expect(Diff{Left: left, Right: right, Equality: eq}.Calculate()).To(Equal(Ops{}))
+		Expect(Diff{Left: left, Right: right}.Calculate()).To(HaveLen(10))
+	})
+
+	It("uses equality in generated test operations", func() {
+		eq := Equality{NormalizeNumbers: true}
+
+		left := map[interface{}]interface{}{"a": 1, "b": "x"}
+		right := map[interface{}]interface{}{"a": 1, "b": "y"}
+
+		ops := Diff{Left: left, Right: right, Equality: eq}.Calculate()
+		Expect(ops).To(Equal(Ops{
+			TestOp{Path: MustNewPointerFromString("/b"), Value: "x", Equality: eq},
+			ReplaceOp{Path: MustNewPointerFromString("/b"), Value: "y"},
+		}))
+
+		res, err := ops.Apply(map[interface{}]interface{}{"a": 1.0, "b": "x"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"a": 1.0, "b": "y"}))
+	})
+})
+
+var _ = Describe("Diff.CalculateWithIgnored", func() {
+	left := map[interface{}]interface{}{
+		"name":    "dep",
+		"version": "1",
+		"certs":   map[interface{}]interface{}{"ca": "old-ca"},
+		"instance_groups": []interface{}{
+			map[interface{}]interface{}{
+				"name":      "api",
+				"instances": 1,
+				"jobs": []interface{}{
+					map[interface{}]interface{}{"name": "cc", "version": "1", "properties": map[interface{}]interface{}{"t": 1}},
+				},
+			},
+			map[interface{}]interface{}{"name": "db", "instances": 1},
+		},
+		"azs": []interface{}{"z1", "z2"},
+	}
+	right := map[interface{}]interface{}{
+		"name":    "dep",
+		"version": "2",
+		"certs":   map[interface{}]interface{}{"ca": "new-ca", "tls": "new-tls"},
+		"instance_groups": []interface{}{
+			map[interface{}]interface{}{
+				"name":      "api",
+				"instances": 2,
+				"jobs": []interface{}{
+					map[interface{}]interface{}{"name": "cc", "version": "2", "properties": map[interface{}]interface{}{"t": 2}},
+				},
+			},
+			map[interface{}]interface{}{"name": "db", "instances": 3},
+		},
+		"azs": []interface{}{"z1", "z3"},
+	}
+
+	It("skips ignored paths and reports the ones that differ", func() {
+		ops, ignored := Diff{
+			Left:      left,
+			Right:     right,
+			Unchecked: true,
+			Ignore: []Pointer{
+				MustNewPointerFromString("/certs"),
+				MustNewPointerFromString("/name"),
+				MustNewPointerFromString("/**/version"),
+				MustNewPointerFromString("/instance_groups/name=db"),
+				MustNewPointerFromString("/instance_groups/*/jobs/*/properties/t"),
+				MustNewPointerFromString("/azs/=z2"),
+			},
+		}.CalculateWithIgnored()
+
+		Expect(ops).To(Equal(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/instance_groups/0/instances"), Value: 2},
+		}))
+
+		Expect(ignored).To(Equal([]Pointer{
+			MustNewPointerFromString("/azs/1"),
+			MustNewPointerFromString("/certs"),
+			MustNewPointerFromString("/instance_groups/0/jobs/0/properties/t"),
+			MustNewPointerFromString("/instance_groups/0/jobs/0/version"),
+			MustNewPointerFromString("/instance_groups/1"),
+			MustNewPointerFromString("/version"),
+		}))
+	})
+
+	It("only compares included paths", func() {
+		ops, ignored := Diff{
+			Left:      left,
+			Right:     right,
+			Unchecked: true,
+			Include: []Pointer{
+				MustNewPointerFromString("/instance_groups/name=api"),
+				MustNewPointerFromString("/certs/tls"),
+			},
+			Ignore: []Pointer{MustNewPointerFromString("/**/properties")},
+		}.CalculateWithIgnored()
+
+		Expect(ops).To(Equal(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/certs/tls?"), Value: "new-tls"},
+			ReplaceOp{Path: MustNewPointerFromString("/instance_groups/0/instances"), Value: 2},
+			ReplaceOp{Path: MustNewPointerFromString("/instance_groups/0/jobs/0/version"), Value: "2"},
+		}))
+
+		Expect(ignored).To(Equal([]Pointer{
+			MustNewPointerFromString("/instance_groups/0/jobs/0/properties"),
+		}))
+	})
+
+	It("compares included paths within values found in one document only", func() {
+		left := map[interface{}]interface{}{
+			"old":  map[interface{}]interface{}{"b": 1, "c": 1},
+			"same": 1,
+		}
+		right := map[interface{}]interface{}{
+			"new":  map[interface{}]interface{}{"b": 2, "c": 2, "d": map[interface{}]interface{}{"b": 3}},
+			"same": 1,
+		}
+
+		ops := Diff{Left: left, Right: right, Include: []Pointer{MustNewPointerFromString("/**/b")}}.Calculate()
+
+		newKey := KeyToken{Key: "new", Optional: true}
+		dKey := KeyToken{Key: "d", Optional: true}
+
+		Expect(ops).To(Equal(Ops{
+			TestOp{Path: NewPointer([]Token{RootToken{}, newKey, KeyToken{Key: "b"}}), Absent: true},
+			ReplaceOp{Path: MustNewPointerFromString("/new?/b?"), Value: 2},
+			TestOp{Path: NewPointer([]Token{RootToken{}, newKey, dKey, KeyToken{Key: "b"}}), Absent: true},
+			ReplaceOp{Path: MustNewPointerFromString("/new?/d?/b?"), Value: 3},
+			TestOp{Path: MustNewPointerFromString("/old/b"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/old/b")},
+		}))
+
+		res, err := ops.Apply(left)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"new":  map[interface{}]interface{}{"b": 2, "d": map[interface{}]interface{}{"b": 3}},
+			"old":  map[interface{}]interface{}{"c": 1},
+			"same": 1,
+		}))
+	})
+
+	It("keeps ignored array items when removing other items", func() {
+		ops, ignored := Diff{
+			Left:   []interface{}{"a", "b", "c"},
+			Right:  []interface{}{"a"},
+			Ignore: []Pointer{MustNewPointerFromString("/=b")},
+		}.CalculateWithIgnored()
+
+		Expect(ignored).To(Equal([]Pointer{MustNewPointerFromString("/1")}))
+
+		res, err := ops.Apply([]interface{}{"a", "b", "c"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal([]interface{}{"a", "b"}))
+	})
+
+	It("ignores whole documents", func() {
+		ops, ignored := Diff{Left: 1, Right: 2, Ignore: []Pointer{MustNewPointerFromString("")}}.CalculateWithIgnored()
+		Expect(ops).To(Equal(Ops{}))
+		Expect(ignored).To(Equal([]Pointer{MustNewPointerFromString("")}))
+
+		ops, ignored = Diff{Left: 1, Right: 1, Ignore: []Pointer{MustNewPointerFromString("")}}.CalculateWithIgnored()
+		Expect(ops).To(Equal(Ops{}))
+		Expect(ignored).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Diff.CalculateContext", func() {
+	left := map[interface{}]interface{}{"a": []interface{}{map[interface{}]interface{}{"b": 1}}}
+	right := map[interface{}]interface{}{"a": []interface{}{map[interface{}]interface{}{"b": 2}}}
+
+	It("returns same operations as Calculate", func() {
+		ops, err := Diff{Left: left, Right: right, Unchecked: true}.CalculateContext(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(Equal(Ops{ReplaceOp{Path: MustNewPointerFromString("/a/0/b"), Value: 2}}))
+	})
+
+	It("returns an error if context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Diff{Left: left, Right: right}.CalculateContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+	})
+})
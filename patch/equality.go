@@ -0,0 +1,107 @@
+package patch
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// Equality determines which values are considered to be equal by test operations and diffs;
+// zero value compares values strictly (same as reflect.DeepEqual)
+type Equality struct {
+	NormalizeNumbers bool // compares numbers regardless of their type (ex: 1, 1.0 and uint64(1))
+	NullAsAbsent     bool // treats null values as missing (ex: {a: null} equals {})
+	IgnoreEmpty      bool // treats empty maps and arrays as missing (ex: {a: {}} equals {})
+}
+
+func (e Equality) Equal(left, right interface{}) bool {
+	if e.absent(left) && e.absent(right) {
+		return true
+	}
+
+	if e.NormalizeNumbers {
+		if leftNum, ok := e.number(left); ok {
+			rightNum, ok := e.number(right)
+			return ok && leftNum.Cmp(rightNum) == 0
+		}
+	}
+
+	switch typedLeft := left.(type) {
+	case map[interface{}]interface{}:
+		typedRight, ok := right.(map[interface{}]interface{})
+		if !ok || (typedLeft == nil) != (typedRight == nil) {
+			return false
+		}
+
+		for k, leftVal := range typedLeft {
+			rightVal, found := typedRight[k]
+			if !found {
+				if !e.absent(leftVal) {
+					return false
+				}
+				continue
+			}
+			if !e.Equal(leftVal, rightVal) {
+				return false
+			}
+		}
+
+		for k, rightVal := range typedRight {
+			if _, found := typedLeft[k]; !found && !e.absent(rightVal) {
+				return false
+			}
+		}
+
+		return true
+
+	case []interface{}:
+		typedRight, ok := right.([]interface{})
+		if !ok || len(typedLeft) != len(typedRight) || (typedLeft == nil) != (typedRight == nil) {
+			return false
+		}
+
+		for i := range typedLeft {
+			if !e.Equal(typedLeft[i], typedRight[i]) {
+				return false
+			}
+		}
+
+		return true
+
+	default:
+		return reflect.DeepEqual(left, right)
+	}
+}
+
+// absent returns true if value is considered to be the same as a missing value
+func (e Equality) absent(val interface{}) bool {
+	switch typedVal := val.(type) {
+	case nil:
+		return e.NullAsAbsent
+	case map[interface{}]interface{}:
+		return e.IgnoreEmpty && len(typedVal) == 0
+	case []interface{}:
+		return e.IgnoreEmpty && len(typedVal) == 0
+	default:
+		return false
+	}
+}
+
+// number converts numeric values without losing precision of large integers
+func (Equality) number(val interface{}) (*big.Float, bool) {
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Float).SetUint64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) {
+			return nil, false
+		}
+		return new(big.Float).SetFloat64(rv.Float()), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,75 @@
+package patch_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("Equality.Equal", func() {
+	It("compares values strictly by default", func() {
+		var eq Equality
+
+		Expect(eq.Equal(nil, nil)).To(BeTrue())
+		Expect(eq.Equal(1, 1)).To(BeTrue())
+		Expect(eq.Equal(1, 1.0)).To(BeFalse())
+		Expect(eq.Equal(1, uint64(1))).To(BeFalse())
+		Expect(eq.Equal(map[interface{}]interface{}{"a": nil}, map[interface{}]interface{}{})).To(BeFalse())
+		Expect(eq.Equal(map[interface{}]interface{}{"a": []interface{}{}}, map[interface{}]interface{}{})).To(BeFalse())
+		Expect(eq.Equal([]interface{}{"a", 1}, []interface{}{"a", 1})).To(BeTrue())
+
+		// nil and empty maps and arrays differ like with reflect.DeepEqual
+		Expect(eq.Equal([]interface{}(nil), []interface{}{})).To(BeFalse())
+		Expect(eq.Equal(map[interface{}]interface{}(nil), map[interface{}]interface{}{})).To(BeFalse())
+		Expect(eq.Equal([]interface{}(nil), []interface{}(nil))).To(BeTrue())
+	})
+
+	It("normalizes numbers", func() {
+		eq := Equality{NormalizeNumbers: true}
+
+		Expect(eq.Equal(1, 1.0)).To(BeTrue())
+		Expect(eq.Equal(uint64(math.MaxUint64), uint64(math.MaxUint64))).To(BeTrue())
+		Expect(eq.Equal(uint64(math.MaxUint64), int64(math.MaxInt64))).To(BeFalse())
+		Expect(eq.Equal(int64(math.MaxInt64), int64(math.MaxInt64-1))).To(BeFalse())
+		Expect(eq.Equal(1, 1.5)).To(BeFalse())
+		Expect(eq.Equal(1, "1")).To(BeFalse())
+		Expect(eq.Equal(math.NaN(), math.NaN())).To(BeFalse())
+
+		Expect(eq.Equal(
+			map[interface{}]interface{}{"a": []interface{}{1, int8(2)}},
+			map[interface{}]interface{}{"a": []interface{}{1.0, uint16(2)}},
+		)).To(BeTrue())
+	})
+
+	It("treats null values as absent", func() {
+		eq := Equality{NullAsAbsent: true}
+
+		Expect(eq.Equal(map[interface{}]interface{}{"a": nil}, map[interface{}]interface{}{})).To(BeTrue())
+		Expect(eq.Equal(map[interface{}]interface{}{}, map[interface{}]interface{}{"a": nil, "b": nil})).To(BeTrue())
+		Expect(eq.Equal(map[interface{}]interface{}{"a": nil}, map[interface{}]interface{}{"a": 1})).To(BeFalse())
+		Expect(eq.Equal(map[interface{}]interface{}{"a": []interface{}{}}, map[interface{}]interface{}{})).To(BeFalse())
+
+		// array items are not absent
+		Expect(eq.Equal([]interface{}{nil}, []interface{}{})).To(BeFalse())
+	})
+
+	It("ignores empty maps and arrays", func() {
+		eq := Equality{IgnoreEmpty: true}
+
+		Expect(eq.Equal(map[interface{}]interface{}{"a": []interface{}{}}, map[interface{}]interface{}{})).To(BeTrue())
+		Expect(eq.Equal(
+			map[interface{}]interface{}{"a": map[interface{}]interface{}{}},
+			map[interface{}]interface{}{"b": []interface{}{}},
+		)).To(BeTrue())
+		Expect(eq.Equal(map[interface{}]interface{}{"a": nil}, map[interface{}]interface{}{})).To(BeFalse())
+		Expect(eq.Equal([]interface{}(nil), []interface{}{})).To(BeTrue())
+
+		eq.NullAsAbsent = true
+
+		Expect(eq.Equal(map[interface{}]interface{}{"a": nil}, map[interface{}]interface{}{"a": map[interface{}]interface{}{}})).To(BeTrue())
+		Expect(eq.Equal(nil, map[interface{}]interface{}{})).To(BeTrue())
+	})
+})
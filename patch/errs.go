@@ -1,12 +1,67 @@
 package patch
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
 	"strings"
 )
 
+// ErrCode identifies kind of error regardless of its message (ex: errors.Is(err, ErrCodeMissingIndex))
+type ErrCode string
+
+const (
+	ErrCodeMismatchType          ErrCode = "mismatch_type"
+	ErrCodeMissingMapKey         ErrCode = "missing_map_key"
+	ErrCodeMissingIndex          ErrCode = "missing_index"
+	ErrCodeMultipleMatchingIndex ErrCode = "multiple_matching_index"
+	ErrCodeUnexpectedToken       ErrCode = "unexpected_token"
+	ErrCodeUnsupportedToken      ErrCode = "unsupported_token"
+	ErrCodeUnexpectedModifier    ErrCode = "unexpected_modifier"
+	ErrCodeMergeConflict         ErrCode = "merge_conflict"
+	ErrCodeSchemaValidation      ErrCode = "schema_validation"
+	ErrCodeTestValueMismatch     ErrCode = "test_value_mismatch"
+	ErrCodeTestUnexpectedValue   ErrCode = "test_unexpected_value"
+	ErrCodeTestMissingValue      ErrCode = "test_missing_value"
+	ErrCodeTestAssertion         ErrCode = "test_assertion"
+	ErrCodeRemoveDocument        ErrCode = "remove_document"
+)
+
+func (c ErrCode) Error() string { return string(c) }
+
+// OpErr is implemented by errors returned by operations so that programs
+// do not have to parse messages; errors marshal into JSON objects with code, path, message and details
+type OpErr interface {
+	error
+	json.Marshaler
+
+	Code() ErrCode
+	Pointer() Pointer
+}
+
+func marshalOpErr(err OpErr, details map[string]interface{}) ([]byte, error) {
+	record := map[string]interface{}{
+		"path":    err.Pointer().String(),
+		"message": err.Error(),
+	}
+
+	if code := err.Code(); len(code) > 0 {
+		record["code"] = code
+	}
+
+	for k, v := range details {
+		record[k] = v
+	}
+
+	return json.Marshal(record)
+}
+
+// typeName returns name of token or modifier type (ex: 'AfterLastIndexToken')
+func typeName(val interface{}) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", val), "patch.")
+}
+
 type OpMismatchTypeErr struct {
 	Type_ string
 	Path  Pointer
@@ -26,15 +81,39 @@ func (e OpMismatchTypeErr) Error() string {
 	return fmt.Sprintf(errMsg, e.Type_, e.Path, e.Obj)
 }
 
+func (OpMismatchTypeErr) Code() ErrCode          { return ErrCodeMismatchType }
+func (e OpMismatchTypeErr) Pointer() Pointer     { return e.Path }
+func (e OpMismatchTypeErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpMismatchTypeErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{"expected_type": e.Type_, "found_type": fmt.Sprintf("%T", e.Obj)})
+}
+
 type OpMissingMapKeyErr struct {
-	Key  string
-	Path Pointer
-	Obj  reflect.Value
+	Key         string
+	Path        Pointer
+	Obj         reflect.Value
+	Suggestions []string // sibling keys similar to the missing key
+}
+
+func NewOpMissingMapKeyErr(key string, path Pointer, obj reflect.Value) OpMissingMapKeyErr {
+	return OpMissingMapKeyErr{Key: key, Path: path, Obj: obj, Suggestions: suggest(key, mapKeys(obj))}
 }
 
 func (e OpMissingMapKeyErr) Error() string {
-	errMsg := "Expected to find a map key '%s' for path '%s' (%s)"
-	return fmt.Sprintf(errMsg, e.Key, e.Path, e.siblingKeysErrStr())
+	errMsg := "Expected to find a map key '%s' for path '%s' (%s%s)"
+	return fmt.Sprintf(errMsg, e.Key, e.Path, e.siblingKeysErrStr(), suggestionsErrStr("; ", e.Suggestions))
+}
+
+func (OpMissingMapKeyErr) Code() ErrCode          { return ErrCodeMissingMapKey }
+func (e OpMissingMapKeyErr) Pointer() Pointer     { return e.Path }
+func (e OpMissingMapKeyErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpMissingMapKeyErr) MarshalJSON() ([]byte, error) {
+	keys := mapKeys(e.Obj)
+	sort.Strings(keys)
+
+	return marshalOpErr(e, map[string]interface{}{"key": e.Key, "found_keys": keys, "suggestions": e.Suggestions})
 }
 
 func (e OpMissingMapKeyErr) siblingKeysErrStr() string {
@@ -42,16 +121,21 @@ func (e OpMissingMapKeyErr) siblingKeysErrStr() string {
 		return "found no other map keys"
 	}
 
+	keys := mapKeys(e.Obj)
+
+	sort.Sort(sort.StringSlice(keys))
+
+	return "found map keys: '" + strings.Join(keys, "', '") + "'"
+}
+
+func mapKeys(obj reflect.Value) []string {
 	var keys []string
-	for _, key := range e.Obj.MapKeys() {
+	for _, key := range obj.MapKeys() {
 		if k := dereference(key); k.Kind() == reflect.String {
 			keys = append(keys, k.String())
 		}
 	}
-
-	sort.Sort(sort.StringSlice(keys))
-
-	return "found map keys: '" + strings.Join(keys, "', '") + "'"
+	return keys
 }
 
 type OpMissingIndexErr struct {
@@ -61,23 +145,383 @@ type OpMissingIndexErr struct {
 }
 
 func (e OpMissingIndexErr) Error() string {
-	return fmt.Sprintf("Expected to find array index '%d' but found array of length '%d' for path '%s'", e.Idx, e.Obj.Len(), e.Path)
+	errMsg := "Expected to find array index '%d' but found array of length '%d' for path '%s' (%s)"
+	return fmt.Sprintf(errMsg, e.Idx, e.Obj.Len(), e.Path, e.validRangeErrStr())
+}
+
+func (OpMissingIndexErr) Code() ErrCode          { return ErrCodeMissingIndex }
+func (e OpMissingIndexErr) Pointer() Pointer     { return e.Path }
+func (e OpMissingIndexErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpMissingIndexErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{"index": e.Idx, "length": e.Obj.Len()})
+}
+
+func (e OpMissingIndexErr) validRangeErrStr() string {
+	length := e.Obj.Len()
+	if length == 0 {
+		return "array is empty"
+	}
+	return fmt.Sprintf("valid indices are 0 to %d or -%d to -1", length-1, length)
 }
 
 type OpMultipleMatchingIndexErr struct {
-	Path Pointer
-	Idxs []int
+	Path        Pointer
+	Idxs        []int
+	Suggestions []string // values similar to the matched value when no items were found (ex: 'name=api')
+}
+
+func NewOpMultipleMatchingIndexErr(path Pointer, idxs []int, array reflect.Value) OpMultipleMatchingIndexErr {
+	err := OpMultipleMatchingIndexErr{Path: path, Idxs: idxs}
+
+	tokens := path.Tokens()
+	if len(idxs) > 0 || array.Kind() != reflect.Slice {
+		return err
+	}
+
+	switch typedToken := tokens[len(tokens)-1].(type) {
+	case MatchingIndexToken:
+		for _, val := range suggest(typedToken.Value, matchingValues(path, array)) {
+			err.Suggestions = append(err.Suggestions, typedToken.Key+"="+val)
+		}
+	case MatchingValueToken:
+		err.Suggestions = suggest(typedToken.Value, matchingValues(path, array))
+	}
+
+	return err
 }
 
 func (e OpMultipleMatchingIndexErr) Error() string {
-	return fmt.Sprintf("Expected to find exactly one matching array item for path '%s' but found %d", e.Path, len(e.Idxs))
+	errMsg := "Expected to find exactly one matching array item for path '%s' but found %d"
+	if len(e.Suggestions) > 0 {
+		errMsg += " (" + suggestionsErrStr("", e.Suggestions) + ")"
+	}
+	return fmt.Sprintf(errMsg, e.Path, len(e.Idxs))
+}
+
+func (OpMultipleMatchingIndexErr) Code() ErrCode          { return ErrCodeMultipleMatchingIndex }
+func (e OpMultipleMatchingIndexErr) Pointer() Pointer     { return e.Path }
+func (e OpMultipleMatchingIndexErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpMultipleMatchingIndexErr) MarshalJSON() ([]byte, error) {
+	idxs := e.Idxs
+	if idxs == nil {
+		idxs = []int{}
+	}
+	return marshalOpErr(e, map[string]interface{}{"indices": idxs, "suggestions": e.Suggestions})
+}
+
+func suggestionsErrStr(prefix string, suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return prefix + "did you mean '" + strings.Join(suggestions, "' or '") + "'?"
 }
 
 type OpUnexpectedTokenErr struct {
-	Token Token
-	Path  Pointer
+	Token    Token
+	Path     Pointer
+	Expected string // description of supported tokens (ex: 'key or matching index')
 }
 
 func (e OpUnexpectedTokenErr) Error() string {
+	if len(e.Expected) > 0 {
+		return fmt.Sprintf("Expected to find %s token at path '%s'", e.Expected, e.Path)
+	}
 	return fmt.Sprintf("Expected to not find token '%T' at path '%s'", e.Token, e.Path)
 }
+
+func (OpUnexpectedTokenErr) Code() ErrCode          { return ErrCodeUnexpectedToken }
+func (e OpUnexpectedTokenErr) Pointer() Pointer     { return e.Path }
+func (e OpUnexpectedTokenErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpUnexpectedTokenErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{"token": typeName(e.Token), "expected": e.Expected})
+}
+
+// OpUnsupportedTokenErr is returned for tokens that operation cannot handle at their position
+type OpUnsupportedTokenErr struct {
+	Token Token
+	Path  Pointer
+	Op    string // operation that does not support token anywhere in path (ex: 'find'); empty if token has to be last
+}
+
+func (e OpUnsupportedTokenErr) Error() string {
+	if len(e.Op) > 0 {
+		errMsg := "Expected not to find after last index token in path '%s' (not supported in %s operations)"
+		return fmt.Sprintf(errMsg, e.Path, e.Op)
+	}
+	if _, ok := e.Token.(AfterLastIndexToken); ok {
+		return fmt.Sprintf("Expected after last index token to be last in path '%s'", e.Path)
+	}
+	return fmt.Sprintf("Expected missing matching value to be last in path '%s'", e.Path)
+}
+
+func (OpUnsupportedTokenErr) Code() ErrCode          { return ErrCodeUnsupportedToken }
+func (e OpUnsupportedTokenErr) Pointer() Pointer     { return e.Path }
+func (e OpUnsupportedTokenErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpUnsupportedTokenErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{"token": typeName(e.Token), "op": e.Op})
+}
+
+type OpUnexpectedModifierErr struct {
+	Modifier Modifier
+	Path     Pointer
+	After    string // modifier that has to be last (ex: 'before'); empty if modifier is not supported
+}
+
+func (e OpUnexpectedModifierErr) Error() string {
+	if len(e.After) > 0 {
+		errMsg := "Expected to not find any modifiers after '%s' modifier, but found modifier '%T'"
+		return fmt.Sprintf(errMsg, e.After, e.Modifier)
+	}
+	errMsg := "Expected to find one of the following modifiers: 'prev', 'next', but found modifier '%T'"
+	return fmt.Sprintf(errMsg, e.Modifier)
+}
+
+func (OpUnexpectedModifierErr) Code() ErrCode          { return ErrCodeUnexpectedModifier }
+func (e OpUnexpectedModifierErr) Pointer() Pointer     { return e.Path }
+func (e OpUnexpectedModifierErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpUnexpectedModifierErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{"modifier": typeName(e.Modifier), "after": e.After})
+}
+
+type OpMergeConflictErr struct {
+	Path      Pointer
+	Conflicts []Pointer
+}
+
+func (e OpMergeConflictErr) Error() string {
+	var paths []string
+	for _, conflict := range e.Conflicts {
+		paths = append(paths, conflict.String())
+	}
+
+	errMsg := "Expected merged values to not conflict with existing values for path '%s' but found conflicts at: '%s'"
+	return fmt.Sprintf(errMsg, e.Path, strings.Join(paths, "', '"))
+}
+
+func (OpMergeConflictErr) Code() ErrCode          { return ErrCodeMergeConflict }
+func (e OpMergeConflictErr) Pointer() Pointer     { return e.Path }
+func (e OpMergeConflictErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpMergeConflictErr) MarshalJSON() ([]byte, error) {
+	conflicts := []string{}
+	for _, conflict := range e.Conflicts {
+		conflicts = append(conflicts, conflict.String())
+	}
+	return marshalOpErr(e, map[string]interface{}{"conflicts": conflicts})
+}
+
+type SchemaValidationErr struct {
+	Path       Pointer
+	Violations []SchemaViolation
+}
+
+func (e SchemaValidationErr) Error() string {
+	var lines []string
+
+	for _, violation := range e.Violations {
+		lines = append(lines, "  - "+violation.String())
+	}
+
+	errMsg := "Expected value at path '%s' to match schema but found %d violation(s):\n%s"
+	return fmt.Sprintf(errMsg, e.Path, len(e.Violations), strings.Join(lines, "\n"))
+}
+
+func (SchemaValidationErr) Code() ErrCode          { return ErrCodeSchemaValidation }
+func (e SchemaValidationErr) Pointer() Pointer     { return e.Path }
+func (e SchemaValidationErr) Is(target error) bool { return target == e.Code() }
+
+func (e SchemaValidationErr) MarshalJSON() ([]byte, error) {
+	violations := []map[string]interface{}{}
+	for _, violation := range e.Violations {
+		violations = append(violations, map[string]interface{}{
+			"path":    violation.Path.String(),
+			"message": violation.Message,
+			"index":   violation.Index,
+		})
+	}
+	return marshalOpErr(e, map[string]interface{}{"violations": violations})
+}
+
+const (
+	testValueMismatchMaxLines    = 10
+	testValueMismatchMaxValueLen = 80
+)
+
+type OpTestValueMismatchErr struct {
+	Path     Pointer
+	Expected interface{}
+	Actual   interface{}
+	Equality Equality
+}
+
+func (e OpTestValueMismatchErr) Error() string {
+	lines := e.diffLines()
+
+	if len(lines) > testValueMismatchMaxLines {
+		more := len(lines) - testValueMismatchMaxLines
+		lines = append(lines[:testValueMismatchMaxLines], fmt.Sprintf("  ... and %d more difference(s)", more))
+	}
+
+	errMsg := "Found value at path '%s' does not match expected value:\n%s"
+	return fmt.Sprintf(errMsg, e.Path, strings.Join(lines, "\n"))
+}
+
+func (OpTestValueMismatchErr) Code() ErrCode          { return ErrCodeTestValueMismatch }
+func (e OpTestValueMismatchErr) Pointer() Pointer     { return e.Path }
+func (e OpTestValueMismatchErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpTestValueMismatchErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{
+		"expected": jsonValue(DefaultRedactionPolicy().Redact(e.Path, e.Expected)),
+		"actual":   jsonValue(DefaultRedactionPolicy().Redact(e.Path, e.Actual)),
+	})
+}
+
+// diffLines lists differences with expected value as the left side of the diff
+func (e OpTestValueMismatchErr) diffLines() []string {
+	var lines []string
+
+	for _, change := range (Diff{Left: e.Expected, Right: e.Actual, Equality: e.Equality}).changes() {
+		path := NewPointer(append(append([]Token{}, e.Path.Tokens()...), change.path.Tokens()[1:]...))
+
+		switch change.kind {
+		case diffChangeAdded:
+			lines = append(lines, e.diffLine(path, "nothing", e.fmtValue(path, change.new)))
+		case diffChangeRemoved:
+			lines = append(lines, e.diffLine(path, e.fmtValue(path, change.old), "nothing"))
+		default:
+			lines = append(lines, e.diffLine(path, e.fmtValue(path, change.old), e.fmtValue(path, change.new)))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, e.diffLine(e.Path, e.fmtValue(e.Path, e.Expected), e.fmtValue(e.Path, e.Actual)))
+	}
+
+	return lines
+}
+
+func (OpTestValueMismatchErr) diffLine(path Pointer, expected, actual string) string {
+	return fmt.Sprintf("  %s: expected %s, found %s", path, expected, actual)
+}
+
+func (OpTestValueMismatchErr) fmtValue(path Pointer, val interface{}) string {
+	runes := []rune(DefaultRedactionPolicy().fmtValue(path, val))
+	if len(runes) > testValueMismatchMaxValueLen {
+		return string(runes[:testValueMismatchMaxValueLen]) + "..."
+	}
+	return string(runes)
+}
+
+type OpTestUnexpectedValueErr struct {
+	Path   Pointer
+	Actual interface{}
+}
+
+func (e OpTestUnexpectedValueErr) Error() string {
+	return fmt.Sprintf("Expected to not find '%s'", e.Path)
+}
+
+func (OpTestUnexpectedValueErr) Code() ErrCode          { return ErrCodeTestUnexpectedValue }
+func (e OpTestUnexpectedValueErr) Pointer() Pointer     { return e.Path }
+func (e OpTestUnexpectedValueErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpTestUnexpectedValueErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{"actual": jsonValue(DefaultRedactionPolicy().Redact(e.Path, e.Actual))})
+}
+
+type OpTestMissingValueErr struct {
+	Path Pointer
+	Err  error // error returned when looking up value
+}
+
+func (e OpTestMissingValueErr) Error() string {
+	return fmt.Sprintf("Expected to find '%s'", e.Path)
+}
+
+func (e OpTestMissingValueErr) Unwrap() error        { return e.Err }
+func (OpTestMissingValueErr) Code() ErrCode          { return ErrCodeTestMissingValue }
+func (e OpTestMissingValueErr) Pointer() Pointer     { return e.Path }
+func (e OpTestMissingValueErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpTestMissingValueErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, nil)
+}
+
+// OpTestAssertionErr is returned when found value does not satisfy one of test operation assertions
+type OpTestAssertionErr struct {
+	Path      Pointer
+	Assertion string      // one of 'is', 'matches', 'min', 'max', 'contains', 'keys', 'length'
+	Expected  interface{} // unsatisfied expectation (ex: missing keys)
+	Actual    interface{}
+	Message   string
+}
+
+func (e OpTestAssertionErr) Error() string { return e.Message }
+
+func (OpTestAssertionErr) Code() ErrCode          { return ErrCodeTestAssertion }
+func (e OpTestAssertionErr) Pointer() Pointer     { return e.Path }
+func (e OpTestAssertionErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpTestAssertionErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, map[string]interface{}{
+		"assertion": e.Assertion,
+		"expected":  jsonValue(DefaultRedactionPolicy().Redact(e.Path, e.Expected)),
+		"actual":    jsonValue(DefaultRedactionPolicy().Redact(e.Path, e.Actual)),
+	})
+}
+
+type OpRemoveDocumentErr struct {
+	Path Pointer
+}
+
+func (OpRemoveDocumentErr) Error() string { return "Cannot remove entire document" }
+
+func (OpRemoveDocumentErr) Code() ErrCode          { return ErrCodeRemoveDocument }
+func (e OpRemoveDocumentErr) Pointer() Pointer     { return e.Path }
+func (e OpRemoveDocumentErr) Is(target error) bool { return target == e.Code() }
+
+func (e OpRemoveDocumentErr) MarshalJSON() ([]byte, error) {
+	return marshalOpErr(e, nil)
+}
+
+// DescriptiveOpErr keeps original error of the operation (available via errors.As)
+type DescriptiveOpErr struct {
+	ErrorMsg string
+	Err      error
+}
+
+func (e DescriptiveOpErr) Error() string {
+	return fmt.Sprintf("Error '%s': %s", e.ErrorMsg, e.Err.Error())
+}
+
+func (e DescriptiveOpErr) Unwrap() error { return e.Err }
+
+// Code returns code of the original error; empty (and omitted from JSON) if original error does not have one
+func (e DescriptiveOpErr) Code() ErrCode {
+	if opErr, ok := e.Err.(OpErr); ok {
+		return opErr.Code()
+	}
+	return ""
+}
+
+func (e DescriptiveOpErr) Pointer() Pointer {
+	if opErr, ok := e.Err.(OpErr); ok {
+		return opErr.Pointer()
+	}
+	return Pointer{}
+}
+
+func (e DescriptiveOpErr) MarshalJSON() ([]byte, error) {
+	var original interface{} = map[string]interface{}{"message": e.Err.Error()}
+	if opErr, ok := e.Err.(OpErr); ok {
+		original = opErr
+	}
+
+	return marshalOpErr(e, map[string]interface{}{"description": e.ErrorMsg, "error": original})
+}
@@ -0,0 +1,189 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("OpErr", func() {
+	doc := map[interface{}]interface{}{
+		"azs":      []interface{}{"z1", "z2"},
+		"password": "secret-value",
+	}
+
+	marshal := func(err error) map[string]interface{} {
+		bytes, marshalErr := json.Marshal(err)
+		Expect(marshalErr).ToNot(HaveOccurred())
+
+		var result map[string]interface{}
+		Expect(json.Unmarshal(bytes, &result)).To(Succeed())
+		return result
+	}
+
+	It("returns codes and paths of errors", func() {
+		_, err := FindOp{Path: MustNewPointerFromString("/azs/5")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+
+		var opErr OpErr
+		Expect(errors.As(err, &opErr)).To(BeTrue())
+		Expect(opErr.Code()).To(Equal(ErrCodeMissingIndex))
+		Expect(opErr.Pointer().String()).To(Equal("/azs/5"))
+
+		Expect(errors.Is(err, ErrCodeMissingIndex)).To(BeTrue())
+		Expect(errors.Is(err, ErrCodeMissingMapKey)).To(BeFalse())
+	})
+
+	It("marshals errors into JSON with code, path, message and details", func() {
+		_, err := FindOp{Path: MustNewPointerFromString("/azs/5")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+
+		Expect(marshal(err)).To(Equal(map[string]interface{}{
+			"code":    "missing_index",
+			"path":    "/azs/5",
+			"message": "Expected to find array index '5' but found array of length '2' for path '/azs/5' (valid indices are 0 to 1 or -2 to -1)",
+			"index":   float64(5),
+			"length":  float64(2),
+		}))
+
+		_, err = FindOp{Path: MustNewPointerFromString("/az")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+
+		record := marshal(err)
+		Expect(record["code"]).To(Equal("missing_map_key"))
+		Expect(record["key"]).To(Equal("az"))
+		Expect(record["found_keys"]).To(Equal([]interface{}{"azs", "password"}))
+	})
+
+	It("redacts values within JSON", func() {
+		_, err := TestOp{Path: MustNewPointerFromString("/password"), Value: "other"}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrCodeTestValueMismatch)).To(BeTrue())
+
+		record := marshal(err)
+		Expect(record["expected"]).To(Equal("<redacted>"))
+		Expect(record["actual"]).To(Equal("<redacted>"))
+	})
+
+	It("returns typed errors for unsupported tokens and modifiers", func() {
+		_, err := FindOp{Path: MustNewPointerFromString("/azs/-")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected not to find after last index token in path '/azs/-' (not supported in find operations)"))
+		Expect(errors.Is(err, ErrCodeUnsupportedToken)).To(BeTrue())
+
+		_, err = ReplaceOp{Path: MustNewPointerFromString("/azs/=z3?/name"), Value: 1}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected missing matching value to be last in path '/azs/=z3?/name'"))
+		Expect(errors.Is(err, ErrCodeUnsupportedToken)).To(BeTrue())
+
+		_, err = ReplaceOp{Path: MustNewPointerFromString("/azs/0:before:after"), Value: "z0"}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to not find any modifiers after 'before' modifier, but found modifier 'patch.AfterModifier'"))
+
+		var modifierErr OpUnexpectedModifierErr
+		Expect(errors.As(err, &modifierErr)).To(BeTrue())
+		Expect(modifierErr.After).To(Equal("before"))
+		Expect(marshal(err)["modifier"]).To(Equal("AfterModifier"))
+	})
+
+	It("returns typed errors for failed tests", func() {
+		_, err := TestOp{Path: MustNewPointerFromString("/azs"), Absent: true}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to not find '/azs'"))
+		Expect(errors.Is(err, ErrCodeTestUnexpectedValue)).To(BeTrue())
+		Expect(marshal(err)["actual"]).To(Equal([]interface{}{"z1", "z2"}))
+
+		_, err = TestOp{Path: MustNewPointerFromString("/az"), Exists: true}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find '/az'"))
+		Expect(errors.Is(err, ErrCodeTestMissingValue)).To(BeTrue())
+		Expect(errors.Is(err, ErrCodeMissingMapKey)).To(BeTrue())
+
+		_, err = TestOp{Path: MustNewPointerFromString("/azs"), Contains: []interface{}{"z3"}}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected array at path '/azs' to contain [\"z3\"] but found [\"z1\",\"z2\"]"))
+
+		var assertionErr OpTestAssertionErr
+		Expect(errors.As(err, &assertionErr)).To(BeTrue())
+		Expect(assertionErr.Assertion).To(Equal("contains"))
+
+		Expect(marshal(err)).To(Equal(map[string]interface{}{
+			"code":      "test_assertion",
+			"path":      "/azs",
+			"message":   "Expected array at path '/azs' to contain [\"z3\"] but found [\"z1\",\"z2\"]",
+			"assertion": "contains",
+			"expected":  []interface{}{"z3"},
+			"actual":    []interface{}{"z1", "z2"},
+		}))
+
+		_, err = TestOp{Path: MustNewPointerFromString("/password"), Matches: "^[a-z]+$"}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrCodeTestAssertion)).To(BeTrue())
+		Expect(marshal(err)["actual"]).To(Equal("<redacted>"))
+	})
+
+	It("returns typed error when removing entire document", func() {
+		_, err := RemoveOp{Path: MustNewPointerFromString("")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Cannot remove entire document"))
+		Expect(errors.Is(err, ErrCodeRemoveDocument)).To(BeTrue())
+		Expect(marshal(err)).To(HaveKeyWithValue("code", "remove_document"))
+	})
+
+	Describe("DescriptiveOpErr", func() {
+		It("keeps original error", func() {
+			_, err := DescriptiveOp{
+				Op:       RemoveOp{Path: MustNewPointerFromString("/az")},
+				ErrorMsg: "removing azs",
+			}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Error 'removing azs': Expected to find a map key 'az' for path '/az' " +
+				"(found map keys: 'azs', 'password')"))
+
+			var keyErr OpMissingMapKeyErr
+			Expect(errors.As(err, &keyErr)).To(BeTrue())
+			Expect(keyErr.Key).To(Equal("az"))
+			Expect(errors.Is(err, ErrCodeMissingMapKey)).To(BeTrue())
+
+			descErr := err.(DescriptiveOpErr)
+			Expect(descErr.Code()).To(Equal(ErrCodeMissingMapKey))
+			Expect(descErr.Pointer().String()).To(Equal("/az"))
+
+			record := marshal(err)
+			Expect(record["code"]).To(Equal("missing_map_key"))
+			Expect(record["path"]).To(Equal("/az"))
+			Expect(record["description"]).To(Equal("removing azs"))
+			Expect(record["error"]).To(HaveKeyWithValue("key", "az"))
+		})
+
+		It("omits code if original error does not have one", func() {
+			_, err := DescriptiveOp{
+				Op:       TestOp{Path: MustNewPointerFromString("/azs/0"), Matches: "("},
+				ErrorMsg: "checking azs",
+			}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(DescriptiveOpErr).Code()).To(BeEmpty())
+
+			record := marshal(err)
+			Expect(record).ToNot(HaveKey("code"))
+			Expect(record["description"]).To(Equal("checking azs"))
+			Expect(record["error"]).To(HaveKeyWithValue("message", ContainSubstring("Expected valid regular expression '('")))
+		})
+
+		It("keeps original error within operations", func() {
+			_, err := Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/azs/-"), Value: "z3"},
+				DescriptiveOp{Op: RemoveOp{Path: MustNewPointerFromString("/azs/=z4")}, ErrorMsg: "removing z4"},
+			}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+
+			var matchErr OpMultipleMatchingIndexErr
+			Expect(errors.As(err, &matchErr)).To(BeTrue())
+			Expect(matchErr.Suggestions).To(BeEmpty())
+		})
+	})
+})
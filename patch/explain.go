@@ -0,0 +1,384 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	ChangeSet      ChangeKind = "set"
+	ChangeInsert   ChangeKind = "insert"
+	ChangeAppend   ChangeKind = "append"
+	ChangeDelete   ChangeKind = "delete"
+	ChangeTestPass ChangeKind = "test-pass"
+)
+
+// Change describes how an operation changed (or tested) the document
+type Change struct {
+	Index int    // index of the top level operation
+	Label string // error message of descriptive operation or 'op N'
+	Path  Pointer
+	Kind  ChangeKind
+	Old   interface{} // not set for created values
+	New   interface{} // not set for deleted values and tests
+}
+
+type Changes []Change
+
+type changeRecord struct {
+	Index int         `json:"index" yaml:"index"`
+	Label string      `json:"label" yaml:"label"`
+	Path  string      `json:"path" yaml:"path"`
+	Kind  ChangeKind  `json:"kind" yaml:"kind"`
+	Old   interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New   interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// Explain applies operations to a copy of the document and
+// returns the result with changes made by each operation
+func (ops Ops) Explain(doc interface{}) (interface{}, Changes, error) {
+	state, err := cloneDoc(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e := &explainer{}
+
+	for i, op := range ops {
+		state, err = e.explain(state, op, i, fmt.Sprintf("op %d", i))
+		if err != nil {
+			return nil, nil, fmt.Errorf("Operation [%d]: %w", i, err)
+		}
+	}
+
+	return state, e.changes, nil
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeSet:
+		if c.Old == nil {
+			return fmt.Sprintf("%s: set %s to %s", c.Label, c.Path, formatChangeValue(c.New))
+		}
+		return fmt.Sprintf("%s: replaced %s %s → %s", c.Label, c.Path, formatChangeValue(c.Old), formatChangeValue(c.New))
+
+	case ChangeInsert:
+		return fmt.Sprintf("%s: inserted %s at %s", c.Label, formatChangeValue(c.New), c.Path)
+
+	case ChangeAppend:
+		tokens := c.Path.Tokens()
+		parent := NewPointer(tokens[:len(tokens)-1])
+		return fmt.Sprintf("%s: appended %s to %s", c.Label, formatChangeValue(c.New), parent)
+
+	case ChangeDelete:
+		return fmt.Sprintf("%s: removed %s (was %s)", c.Label, c.Path, formatChangeValue(c.Old))
+
+	default:
+		return fmt.Sprintf("%s: tested %s", c.Label, c.Path)
+	}
+}
+
+func (c Change) MarshalYAML() (interface{}, error) {
+	return changeRecord{c.Index, c.Label, c.Path.String(), c.Kind, c.Old, c.New}, nil
+}
+
+func (c Change) MarshalJSON() ([]byte, error) {
+	return json.Marshal(changeRecord{c.Index, c.Label, c.Path.String(), c.Kind, jsonValue(c.Old), jsonValue(c.New)})
+}
+
+func (cs Changes) String() string {
+	var lines []string
+
+	for _, c := range cs {
+		lines = append(lines, c.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Redact returns changes with values hidden according to the policy (ex: before logging them)
+func (cs Changes) Redact(policy RedactionPolicy) Changes {
+	var result Changes
+
+	for _, c := range cs {
+		if c.Old != nil {
+			c.Old = policy.Redact(c.Path, c.Old)
+		}
+		if c.New != nil {
+			c.New = policy.Redact(c.Path, c.New)
+		}
+		result = append(result, c)
+	}
+
+	return result
+}
+
+type explainer struct {
+	changes Changes
+}
+
+func (e *explainer) explain(doc interface{}, op Op, index int, label string) (interface{}, error) {
+	var path Pointer
+
+	switch typedOp := op.(type) {
+	case DescriptiveOp:
+		doc, err := e.explain(doc, typedOp.Op, index, typedOp.ErrorMsg)
+		if err != nil {
+			return nil, fmt.Errorf("Error '%s': %s", typedOp.ErrorMsg, err.Error())
+		}
+		return doc, nil
+
+	case Ops:
+		return e.explainAll(doc, typedOp, index, label)
+
+	case GroupOp:
+		ops, err := typedOp.absoluteOps()
+		if err != nil {
+			return nil, err
+		}
+		return e.explainAll(doc, ops, index, label)
+
+	case IfOp:
+		if _, err := typedOp.Test.Apply(doc); err != nil {
+			return e.explainAll(doc, typedOp.Else, index, label)
+		}
+		return e.explainAll(doc, typedOp.Then, index, label)
+
+	case ReplaceOp:
+		path = typedOp.Path
+	case RemoveOp:
+		path = typedOp.Path
+	case TestOp:
+		path = typedOp.Path
+	case MergeOp:
+		path = typedOp.Path
+	case StrategicMergeOp:
+		path = typedOp.Path
+
+	default:
+		return op.Apply(doc)
+	}
+
+	loc, locErr := e.locate(doc, path)
+	if locErr != nil {
+		// operation is expected to fail as well or to not use resolved location (e.g. absence test)
+		loc = location{path: path, kind: ChangeSet}
+	}
+
+	before, err := cloneDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = op.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	change := Change{Index: index, Label: label, Path: loc.path, Kind: loc.kind}
+
+	if loc.found {
+		change.Old = loc.value
+	}
+
+	switch op.(type) {
+	case TestOp:
+		change.Kind = ChangeTestPass
+
+	case RemoveOp:
+		if reflect.DeepEqual(before, doc) {
+			return doc, nil
+		}
+		change.Kind = ChangeDelete
+
+	default:
+		if reflect.DeepEqual(before, doc) {
+			return doc, nil
+		}
+		change.New, _ = FindOp{Path: loc.path}.Apply(doc)
+	}
+
+	e.changes = append(e.changes, change)
+
+	return doc, nil
+}
+
+func (e *explainer) explainAll(doc interface{}, ops Ops, index int, label string) (interface{}, error) {
+	var err error
+
+	for _, op := range ops {
+		doc, err = e.explain(doc, op, index, label)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+type location struct {
+	path  Pointer // concrete pointer without optional tokens and modifiers
+	kind  ChangeKind
+	found bool
+	value interface{}
+}
+
+// locate resolves pointer against the document the same way replace operation does
+func (explainer) locate(doc interface{}, ptr Pointer) (location, error) {
+	tokens := ptr.Tokens()
+	concrete := []Token{RootToken{}}
+
+	loc := location{kind: ChangeSet, found: true, value: doc}
+
+	for i, token := range tokens[1:] {
+		isLast := i == len(tokens)-2
+		currPath := NewPointer(tokens[:i+2])
+
+		// kind of change is determined by the last token
+		loc.kind = ChangeSet
+
+		if !loc.found {
+			// missing parents are created with a single item in new arrays
+			if typedToken, ok := token.(KeyToken); ok {
+				concrete = append(concrete, KeyToken{Key: typedToken.Key})
+			} else {
+				concrete = append(concrete, IndexToken{Index: 0})
+				loc.kind = ChangeAppend
+			}
+			continue
+		}
+
+		obj := loc.value
+
+		if typedToken, ok := token.(KeyToken); ok {
+			val := reflect.ValueOf(obj)
+			if val.Kind() != reflect.Map {
+				return location{}, NewOpMapMismatchTypeErr(currPath, obj)
+			}
+
+			concrete = append(concrete, KeyToken{Key: typedToken.Key})
+
+			if mapValue := val.MapIndex(reflect.ValueOf(typedToken.Key)); mapValue.IsValid() {
+				loc.value = mapValue.Interface()
+			} else {
+				loc.found, loc.value = false, nil
+			}
+			continue
+		}
+
+		array := reflect.ValueOf(obj)
+		if array.Kind() != reflect.Slice {
+			return location{}, NewOpArrayMismatchTypeErr(currPath, obj)
+		}
+
+		var idx int
+		var modifiers []Modifier
+		var appended bool
+
+		switch typedToken := token.(type) {
+		case IndexToken:
+			idx, modifiers = typedToken.Index, typedToken.Modifiers
+
+		case AfterLastIndexToken:
+			appended = true
+
+		case MatchingIndexToken:
+			idxs := findMapIndices(array, typedToken.Key, typedToken.Value)
+			if typedToken.Optional && len(idxs) == 0 {
+				appended = true
+			} else if len(idxs) != 1 {
+				return location{}, NewOpMultipleMatchingIndexErr(currPath, idxs, array)
+			} else {
+				idx, modifiers = idxs[0], typedToken.Modifiers
+			}
+
+		case MatchingValueToken:
+			idxs := findValueIndices(array, typedToken.Value)
+			if typedToken.Optional && len(idxs) == 0 {
+				appended = true
+			} else if len(idxs) != 1 {
+				return location{}, NewOpMultipleMatchingIndexErr(currPath, idxs, array)
+			} else {
+				idx, modifiers = idxs[0], typedToken.Modifiers
+			}
+
+		default:
+			return location{}, OpUnexpectedTokenErr{Token: token, Path: currPath}
+		}
+
+		switch {
+		case appended:
+			concrete = append(concrete, IndexToken{Index: array.Len()})
+			loc.kind, loc.found, loc.value = ChangeAppend, false, nil
+
+		case isLast:
+			insertion, err := ArrayInsertion{Index: idx, Modifiers: modifiers, Array: array, Path: currPath}.Concrete()
+			if err != nil {
+				return location{}, err
+			}
+
+			concrete = append(concrete, IndexToken{Index: insertion.number})
+
+			if insertion.insert {
+				loc.kind, loc.found, loc.value = ChangeInsert, false, nil
+			} else {
+				loc.value = array.Index(insertion.number).Interface()
+			}
+
+		default:
+			num, err := ArrayIndex{Index: idx, Modifiers: modifiers, Array: array, Path: currPath}.Concrete()
+			if err != nil {
+				return location{}, err
+			}
+
+			concrete = append(concrete, IndexToken{Index: num})
+			loc.value = array.Index(num).Interface()
+		}
+	}
+
+	loc.path = NewPointer(concrete)
+
+	return loc, nil
+}
+
+func formatChangeValue(val interface{}) string {
+	var buf bytes.Buffer
+
+	// keep redacted values readable
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(jsonValue(val))
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// jsonValue converts maps with interface keys (produced by YAML library) to be JSON serializable
+func jsonValue(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for k, v := range typedVal {
+			result[fmt.Sprintf("%v", k)] = jsonValue(v)
+		}
+		return result
+
+	case []interface{}:
+		result := []interface{}{}
+		for _, v := range typedVal {
+			result = append(result, jsonValue(v))
+		}
+		return result
+
+	default:
+		return val
+	}
+}
@@ -0,0 +1,184 @@
+package patch_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("Ops.Explain", func() {
+	var doc interface{}
+
+	BeforeEach(func() {
+		doc = map[interface{}]interface{}{
+			"name": "dep",
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{"name": "api", "instances": 0, "azs": []interface{}{"z1"}},
+			},
+		}
+	})
+
+	It("returns result and changes made by each operation without modifying document", func() {
+		result, changes, err := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/instance_groups/name=api/instances"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/instance_groups/-"), Value: map[interface{}]interface{}{"name": "db"}},
+			DescriptiveOp{
+				Op:       ReplaceOp{Path: MustNewPointerFromString("/instance_groups/name=api/azs/0:before"), Value: "z0"},
+				ErrorMsg: "add z0",
+			},
+			RemoveOp{Path: MustNewPointerFromString("/name")},
+			TestOp{Path: MustNewPointerFromString("/instance_groups/name=db/name"), Value: "db"},
+			ReplaceOp{Path: MustNewPointerFromString("/new?/key"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/missing?")},
+		}.Explain(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(changes).To(Equal(Changes{
+			{
+				Index: 0,
+				Label: "op 0",
+				Path:  MustNewPointerFromString("/instance_groups/0/instances"),
+				Kind:  ChangeSet,
+				Old:   0,
+				New:   1,
+			},
+			{
+				Index: 1,
+				Label: "op 1",
+				Path:  MustNewPointerFromString("/instance_groups/1"),
+				Kind:  ChangeAppend,
+				New:   map[interface{}]interface{}{"name": "db"},
+			},
+			{
+				Index: 2,
+				Label: "add z0",
+				Path:  MustNewPointerFromString("/instance_groups/0/azs/0"),
+				Kind:  ChangeInsert,
+				New:   "z0",
+			},
+			{
+				Index: 3,
+				Label: "op 3",
+				Path:  MustNewPointerFromString("/name"),
+				Kind:  ChangeDelete,
+				Old:   "dep",
+			},
+			{
+				Index: 4,
+				Label: "op 4",
+				Path:  MustNewPointerFromString("/instance_groups/1/name"),
+				Kind:  ChangeTestPass,
+				Old:   "db",
+			},
+			{
+				Index: 5,
+				Label: "op 5",
+				Path:  MustNewPointerFromString("/new/key"),
+				Kind:  ChangeSet,
+				New:   1,
+			},
+		}))
+
+		Expect(changes.String()).To(Equal(`op 0: replaced /instance_groups/0/instances 0 → 1
+op 1: appended {"name":"db"} to /instance_groups
+add z0: inserted "z0" at /instance_groups/0/azs/0
+op 3: removed /name (was "dep")
+op 4: tested /instance_groups/1/name
+op 5: set /new/key to 1`))
+
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{"name": "api", "instances": 1, "azs": []interface{}{"z0", "z1"}},
+				map[interface{}]interface{}{"name": "db"},
+			},
+			"new": map[interface{}]interface{}{"key": 1},
+		}))
+
+		Expect(doc.(map[interface{}]interface{})["name"]).To(Equal("dep"))
+	})
+
+	It("explains nested operations with index of top level operation", func() {
+		_, changes, err := Ops{
+			GroupOp{Path: MustNewPointerFromString("/instance_groups/name=api"), Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/jobs?/name=x?/release?"), Value: "r"},
+			}},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/name"), Absent: true},
+				Then: Ops{ReplaceOp{Path: MustNewPointerFromString("/name?"), Value: "new"}},
+				Else: Ops{ReplaceOp{Path: MustNewPointerFromString("/name"), Value: "other"}},
+			},
+		}.Explain(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal(Changes{
+			{
+				Index: 0,
+				Label: "op 0",
+				Path:  MustNewPointerFromString("/instance_groups/0/jobs/0/release"),
+				Kind:  ChangeSet,
+				New:   "r",
+			},
+			{
+				Index: 1,
+				Label: "op 1",
+				Path:  MustNewPointerFromString("/name"),
+				Kind:  ChangeSet,
+				Old:   "dep",
+				New:   "other",
+			},
+		}))
+	})
+
+	It("does not report operations that do not change document", func() {
+		_, changes, err := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/instance_groups/0/azs/-"), Value: "z1", Idempotent: true},
+			ReplaceOp{Path: MustNewPointerFromString("/name"), Value: "dep"},
+			FindOp{Path: MustNewPointerFromString("/name")},
+		}.Explain(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("returns an error if operation cannot be applied", func() {
+		_, _, err := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/name"), Value: "new"},
+			DescriptiveOp{Op: RemoveOp{Path: MustNewPointerFromString("/missing")}, ErrorMsg: "desc"},
+		}.Explain(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Operation [1]: Error 'desc': Expected to find a map key 'missing' " +
+			"for path '/missing' (found map keys: 'instance_groups', 'name')"))
+	})
+
+	It("serializes changes as YAML and JSON", func() {
+		changes := Changes{{
+			Index: 1,
+			Label: "op 1",
+			Path:  MustNewPointerFromString("/a/0"),
+			Kind:  ChangeSet,
+			Old:   map[interface{}]interface{}{"b": 1},
+			New:   2,
+		}}
+
+		bytes, err := yaml.Marshal(changes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes)).To(Equal(`- index: 1
+  label: op 1
+  path: /a/0
+  kind: set
+  old:
+    b: 1
+  new: 2
+`))
+
+		bytes, err = json.Marshal(changes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes)).To(Equal(`[{"index":1,"label":"op 1","path":"/a/0","kind":"set","old":{"b":1},"new":2}]`))
+	})
+})
@@ -1,7 +1,7 @@
 package patch
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 )
 
@@ -10,6 +10,14 @@ type FindOp struct {
 }
 
 func (op FindOp) Apply(doc interface{}) (interface{}, error) {
+	return op.apply(context.Background(), doc)
+}
+
+func (op FindOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	return op.apply(ctx, doc)
+}
+
+func (op FindOp) apply(ctx context.Context, doc interface{}) (interface{}, error) {
 	tokens := op.Path.Tokens()
 
 	if len(tokens) == 1 {
@@ -41,8 +49,7 @@ func (op FindOp) Apply(doc interface{}) (interface{}, error) {
 			}
 
 		case AfterLastIndexToken:
-			errMsg := "Expected not to find after last index token in path '%s' (not supported in find operations)"
-			return nil, fmt.Errorf(errMsg, op.Path)
+			return nil, OpUnsupportedTokenErr{Token: typedToken, Path: op.Path, Op: "find"}
 
 		case MatchingIndexToken:
 			ptr := reflect.ValueOf(obj)
@@ -50,7 +57,10 @@ func (op FindOp) Apply(doc interface{}) (interface{}, error) {
 				return nil, NewOpArrayMismatchTypeErr(currPath, obj)
 			}
 
-			idxs := findMapIndices(ptr, typedToken.Key, typedToken.Value)
+			idxs, err := findMapIndicesContext(ctx, ptr, typedToken.Key, typedToken.Value)
+			if err != nil {
+				return nil, err
+			}
 
 
 			if typedToken.Optional && len(idxs) == 0 {
@@ -62,7 +72,7 @@ func (op FindOp) Apply(doc interface{}) (interface{}, error) {
 				}
 			} else {
 				if len(idxs) != 1 {
-					return nil, OpMultipleMatchingIndexErr{currPath, idxs}
+					return nil, NewOpMultipleMatchingIndexErr(currPath, idxs, ptr)
 				}
 
 				idx, err := ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
@@ -77,6 +87,37 @@ func (op FindOp) Apply(doc interface{}) (interface{}, error) {
 				}
 			}
 
+		case MatchingValueToken:
+			ptr := reflect.ValueOf(obj)
+			if ptr.Kind() != reflect.Slice {
+				return nil, NewOpArrayMismatchTypeErr(currPath, obj)
+			}
+
+			idxs, err := findValueIndicesContext(ctx, ptr, typedToken.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			if typedToken.Optional && len(idxs) == 0 {
+				// scalar values cannot contain anything else
+				return nil, nil
+			}
+
+			if len(idxs) != 1 {
+				return nil, NewOpMultipleMatchingIndexErr(currPath, idxs, ptr)
+			}
+
+			idx, err := ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
+			if err != nil {
+				return nil, err
+			}
+
+			if isLast {
+				return ptr.Index(idx).Interface(), nil
+			} else {
+				obj = ptr.Index(idx).Interface()
+			}
+
 		case KeyToken:
 			ptr := reflect.ValueOf(obj)
 			if ptr.Kind() != reflect.Map {
@@ -90,7 +131,7 @@ func (op FindOp) Apply(doc interface{}) (interface{}, error) {
 				found = true
 			} else {
 				if !typedToken.Optional {
-					return nil, OpMissingMapKeyErr{typedToken.Key, currPath, ptr}
+					return nil, NewOpMissingMapKeyErr(typedToken.Key, currPath, ptr)
 				}
 
 				found = false
@@ -106,19 +147,19 @@ func (op FindOp) Apply(doc interface{}) (interface{}, error) {
 				if !found {
 					// Determine what type of value to create based on next token
 					switch tokens[i+2].(type) {
-					case MatchingIndexToken:
+					case MatchingIndexToken, MatchingValueToken:
 						obj = []interface{}{}
 					case KeyToken:
 						obj = map[interface{}]interface{}{}
 					default:
-						errMsg := "Expected to find key or matching index token at path '%s'"
-						return nil, fmt.Errorf(errMsg, NewPointer(tokens[:i+3]))
+						path := NewPointer(tokens[:i+3])
+						return nil, OpUnexpectedTokenErr{Token: tokens[i+2], Path: path, Expected: "key or matching index"}
 					}
 				}
 			}
 
 		default:
-			return nil, OpUnexpectedTokenErr{token, currPath}
+			return nil, OpUnexpectedTokenErr{Token: token, Path: currPath}
 		}
 	}
 
@@ -81,12 +81,12 @@ var _ = Describe("FindOp.Apply", func() {
 			_, err := FindOp{Path: MustNewPointerFromString("/1")}.Apply([]interface{}{})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find array index '1' but found array of length '0' for path '/1'"))
+				"Expected to find array index '1' but found array of length '0' for path '/1' (array is empty)"))
 
 			_, err = FindOp{Path: MustNewPointerFromString("/1/1")}.Apply([]interface{}{})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find array index '1' but found array of length '0' for path '/1'"))
+				"Expected to find array index '1' but found array of length '0' for path '/1' (array is empty)"))
 		})
 	})
 
@@ -174,7 +174,7 @@ var _ = Describe("FindOp.Apply", func() {
 			_, err := FindOp{Path: MustNewPointerFromString("/key=val")}.Apply(doc)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find exactly one matching array item for path '/key=val' but found 0"))
+				"Expected to find exactly one matching array item for path '/key=val' but found 0 (did you mean 'key=val2'?)"))
 		})
 
 		It("returns an error if multiple items found", func() {
@@ -263,6 +263,73 @@ var _ = Describe("FindOp.Apply", func() {
 		})
 	})
 
+	Describe("array item with matching value", func() {
+		It("finds scalar array item", func() {
+			doc := map[interface{}]interface{}{"azs": []interface{}{"z1", "z2"}, "ports": []interface{}{80, 443}}
+
+			res, err := FindOp{Path: MustNewPointerFromString("/azs/=z2")}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal("z2"))
+
+			res, err = FindOp{Path: MustNewPointerFromString("/ports/=443")}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(443))
+		})
+
+		It("finds relative scalar array item", func() {
+			doc := []interface{}{"z1", "z2", "z3"}
+
+			res, err := FindOp{Path: MustNewPointerFromString("/=z2:next")}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal("z3"))
+		})
+
+		It("does not match maps or arrays", func() {
+			doc := []interface{}{map[interface{}]interface{}{"": "z1"}, []interface{}{"z1"}}
+
+			_, err := FindOp{Path: MustNewPointerFromString("/=z1")}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find exactly one matching array item for path '/=z1' but found 0"))
+
+			_, err = FindOp{Path: MustNewPointerFromString("/=[z1]")}.Apply([]interface{}{[1]string{"z1"}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find exactly one matching array item for path '/=[z1]' but found 0"))
+		})
+
+		It("matches scalars by their string representation", func() {
+			res, err := FindOp{Path: MustNewPointerFromString("/=true")}.Apply([]interface{}{false, true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(true))
+
+			_, err = FindOp{Path: MustNewPointerFromString("/=1")}.Apply([]interface{}{1, "1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find exactly one matching array item for path '/=1' but found 2"))
+		})
+
+		It("returns an error if multiple items found", func() {
+			_, err := FindOp{Path: MustNewPointerFromString("/=z1")}.Apply([]interface{}{"z1", "z1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find exactly one matching array item for path '/=z1' but found 2"))
+		})
+
+		It("returns nil for missing item if item is not expected to exist", func() {
+			res, err := FindOp{Path: MustNewPointerFromString("/azs?/=z3")}.Apply(map[interface{}]interface{}{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("returns an error if it's not an array is being accessed", func() {
+			_, err := FindOp{Path: MustNewPointerFromString("/=z1")}.Apply(map[interface{}]interface{}{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find an array at path '/=z1' but found 'map[interface {}]interface {}'"))
+		})
+	})
+
 	Describe("map key", func() {
 		It("finds map key", func() {
 			doc := map[interface{}]interface{}{
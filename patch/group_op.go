@@ -0,0 +1,150 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+)
+
+// GroupOp applies operations with paths relative to the group path
+type GroupOp struct {
+	Path Pointer
+	Ops  Ops
+}
+
+func (op GroupOp) Apply(doc interface{}) (interface{}, error) {
+	return op.ApplyContext(context.Background(), doc)
+}
+
+func (op GroupOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	ops, err := op.absoluteOps()
+	if err != nil {
+		return nil, err
+	}
+
+	return ops.ApplyContext(ctx, doc)
+}
+
+func (op GroupOp) absoluteOps() (Ops, error) {
+	var ops Ops
+
+	for i, childOp := range op.Ops {
+		absOp, err := rebaseOp(childOp, op.Path)
+		if err != nil {
+			return nil, fmt.Errorf("Group operation [%d] within '%s': %s", i, op.Path, err)
+		}
+
+		ops = append(ops, absOp)
+	}
+
+	return ops, nil
+}
+
+// rebaseOp returns an operation with paths made relative to base pointer
+func rebaseOp(op Op, base Pointer) (Op, error) {
+	if path, found := opPath(op); found && !path.IsSet() {
+		return nil, fmt.Errorf("Expected operation '%T' to have a path", op)
+	}
+
+	switch typedOp := op.(type) {
+	case ReplaceOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case RemoveOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case TestOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case FindOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case MergeOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case StrategicMergeOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+
+		if typedOp.MergeKeys != nil && len(base.Tokens()) > 1 {
+			mergeKeys := map[string]string{}
+			for path, key := range typedOp.MergeKeys {
+				mergeKeys[base.String()+path] = key
+			}
+			typedOp.MergeKeys = mergeKeys
+		}
+
+		return typedOp, nil
+
+	case GroupOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case ValidateOp:
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+
+	case IfOp:
+		test, err := rebaseOp(typedOp.Test, base)
+		if err != nil {
+			return nil, err
+		}
+
+		typedOp.Test = test.(TestOp)
+
+		typedOp.Then, err = rebaseOps(typedOp.Then, base)
+		if err != nil {
+			return nil, err
+		}
+
+		typedOp.Else, err = rebaseOps(typedOp.Else, base)
+		if err != nil {
+			return nil, err
+		}
+
+		return typedOp, nil
+
+	case DescriptiveOp:
+		childOp, err := rebaseOp(typedOp.Op, base)
+		if err != nil {
+			return nil, err
+		}
+
+		typedOp.Op = childOp
+		return typedOp, nil
+
+	case Ops:
+		return rebaseOps(typedOp, base)
+
+	case ErrOp:
+		return typedOp, nil
+
+	default:
+		if opType, found := registeredOpTypeOf(op); found && opType.Rebase != nil {
+			return opType.Rebase(op, base)
+		}
+		return nil, fmt.Errorf("Expected to find operation with a relative path but found '%T'", op)
+	}
+}
+
+func rebaseOps(ops Ops, base Pointer) (Ops, error) {
+	if ops == nil {
+		return nil, nil
+	}
+
+	result := Ops{}
+
+	for _, op := range ops {
+		absOp, err := rebaseOp(op, base)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, absOp)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package patch_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("GroupOp.Apply", func() {
+	var doc interface{}
+
+	BeforeEach(func() {
+		doc = map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{
+					"name": "diego-cell",
+					"jobs": []interface{}{
+						map[interface{}]interface{}{"name": "rep", "properties": map[interface{}]interface{}{"a": 1}},
+					},
+				},
+			},
+		}
+	})
+
+	It("applies operations relative to group path", func() {
+		res, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups/name=diego-cell"),
+			Ops: Ops{
+				TestOp{Path: MustNewPointerFromString("/jobs/name=rep/properties/a"), Value: 1},
+				ReplaceOp{Path: MustNewPointerFromString("/jobs/name=rep/properties/a"), Value: 2},
+				ReplaceOp{Path: MustNewPointerFromString("/instances?"), Value: 3},
+				RemoveOp{Path: MustNewPointerFromString("/jobs/name=rep/properties")},
+			},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{
+					"name":      "diego-cell",
+					"instances": 3,
+					"jobs": []interface{}{
+						map[interface{}]interface{}{"name": "rep"},
+					},
+				},
+			},
+		}))
+	})
+
+	It("supports nested groups, conditionals and descriptive operations", func() {
+		res, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups/name=diego-cell"),
+			Ops: Ops{
+				GroupOp{
+					Path: MustNewPointerFromString("/jobs/name=rep"),
+					Ops: Ops{
+						IfOp{
+							Test: TestOp{Path: MustNewPointerFromString("/properties/a"), Value: 1},
+							Then: Ops{DescriptiveOp{
+								Op:       ReplaceOp{Path: MustNewPointerFromString("/properties/b?"), Value: 2},
+								ErrorMsg: "msg",
+							}},
+						},
+					},
+				},
+			},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{
+					"name": "diego-cell",
+					"jobs": []interface{}{
+						map[interface{}]interface{}{"name": "rep", "properties": map[interface{}]interface{}{"a": 1, "b": 2}},
+					},
+				},
+			},
+		}))
+	})
+
+	It("carries over optionality of group path", func() {
+		res, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups/name=router?"),
+			Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 1},
+			},
+		}.Apply(map[interface{}]interface{}{"instance_groups": []interface{}{}})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{"name": "router", "instances": 1},
+			},
+		}))
+	})
+
+	It("applies merge keys of strategic merge operations with optional paths", func() {
+		doc := map[interface{}]interface{}{
+			"jobs": []interface{}{
+				map[interface{}]interface{}{"name": "rep", "links": []interface{}{map[interface{}]interface{}{"name": "a"}}},
+			},
+		}
+
+		res, err := GroupOp{
+			Path: MustNewPointerFromString("/jobs/name=rep"),
+			Ops: Ops{
+				StrategicMergeOp{
+					Path:      MustNewPointerFromString("/links?"),
+					Value:     []interface{}{map[interface{}]interface{}{"name": "b"}},
+					MergeKeys: map[string]string{"/links": ""},
+				},
+			},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"jobs": []interface{}{
+				map[interface{}]interface{}{"name": "rep", "links": []interface{}{map[interface{}]interface{}{"name": "b"}}},
+			},
+		}))
+	})
+
+	It("returns errors with absolute paths", func() {
+		_, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups/name=diego-cell"),
+			Ops: Ops{
+				RemoveOp{Path: MustNewPointerFromString("/missing")},
+			},
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find a map key 'missing' for path '/instance_groups/name=diego-cell/missing' (found map keys: 'jobs', 'name')"))
+	})
+
+	It("returns errors from operations", func() {
+		_, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups"),
+			Ops:  Ops{ErrOp{Err: errors.New("fake-err")}},
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("fake-err"))
+	})
+
+	It("returns an error for operations that cannot be made relative", func() {
+		_, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups"),
+			Ops:  Ops{FakeOp{}},
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Group operation [0] within '/instance_groups': Expected to find operation with a relative path but found 'patch_test.FakeOp'"))
+	})
+
+	It("returns an error for operations without a path", func() {
+		_, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups"),
+			Ops:  Ops{RemoveOp{}},
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Group operation [0] within '/instance_groups': Expected operation 'patch.RemoveOp' to have a path"))
+	})
+})
+
+type FakeOp struct{}
+
+func (FakeOp) Apply(doc interface{}) (interface{}, error) { return doc, nil }
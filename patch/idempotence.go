@@ -0,0 +1,204 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// IdempotenceViolation describes an operation that changes
+// the document (or fails) when operations are applied again
+type IdempotenceViolation struct {
+	Index int
+	Path  Pointer // not set for operations without a path
+	Err   error   // set if operation fails when applied again
+}
+
+func (v IdempotenceViolation) String() string {
+	desc := fmt.Sprintf("Operation [%d]", v.Index)
+
+	if v.Path.IsSet() {
+		desc += fmt.Sprintf(" for path '%s'", v.Path)
+	}
+
+	if v.Err != nil {
+		return fmt.Sprintf("%s fails when applied again: %s", desc, v.Err)
+	}
+
+	return desc + " changes document when applied again"
+}
+
+// CheckIdempotence applies operations to a copy of the document twice and
+// returns operations that prevent ops.Apply(ops.Apply(doc)) from equaling ops.Apply(doc).
+// Each operation is also immediately re-applied to catch duplicate appends and inserts.
+func CheckIdempotence(ops Ops, doc interface{}) ([]IdempotenceViolation, error) {
+	var violations []IdempotenceViolation
+
+	reported := map[int]struct{}{}
+
+	report := func(i int, err error) {
+		if _, found := reported[i]; found {
+			return
+		}
+
+		path, _ := opPath(ops[i])
+		violations = append(violations, IdempotenceViolation{Index: i, Path: path, Err: err})
+		reported[i] = struct{}{}
+	}
+
+	// Documents are cloned once per pass; re-applied operations are checked
+	// against a second copy that is kept in sync instead of cloning per operation
+	state, err := cloneDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	again, err := cloneDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		state, err = op.Apply(state)
+		if err != nil {
+			return nil, fmt.Errorf("Operation [%d]: %s", i, err)
+		}
+
+		again, err = op.Apply(again)
+		if err == nil {
+			again, err = op.Apply(again)
+		}
+
+		if err != nil || !reflect.DeepEqual(again, state) {
+			report(i, err)
+
+			again, err = cloneDoc(state)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Detect operations that interfere with each other
+	secondState, err := cloneDoc(state)
+	if err != nil {
+		return nil, err
+	}
+
+	before := again // same as state
+
+	var changing []int
+
+	for i, op := range ops {
+		secondState, err = op.Apply(secondState)
+		if err != nil {
+			report(i, err)
+			return violations, nil
+		}
+
+		if !reflect.DeepEqual(before, secondState) {
+			changing = append(changing, i)
+		}
+
+		before, err = op.Apply(before)
+		if err != nil {
+			report(i, err)
+			return violations, nil
+		}
+	}
+
+	if !reflect.DeepEqual(state, secondState) && len(violations) == 0 {
+		for _, i := range changing {
+			report(i, nil)
+		}
+	}
+
+	return violations, nil
+}
+
+// Idempotent returns operations (including nested ones) that skip
+// appending or inserting values that are already present
+func (ops Ops) Idempotent() Ops {
+	if ops == nil {
+		return nil
+	}
+
+	result := Ops{}
+
+	for _, op := range ops {
+		result = append(result, idempotentOp(op))
+	}
+
+	return result
+}
+
+func idempotentOp(op Op) Op {
+	switch typedOp := op.(type) {
+	case ReplaceOp:
+		typedOp.Idempotent = true
+		return typedOp
+
+	case DescriptiveOp:
+		typedOp.Op = idempotentOp(typedOp.Op)
+		return typedOp
+
+	case IfOp:
+		typedOp.Then = typedOp.Then.Idempotent()
+		typedOp.Else = typedOp.Else.Idempotent()
+		return typedOp
+
+	case GroupOp:
+		typedOp.Ops = typedOp.Ops.Idempotent()
+		return typedOp
+
+	case Ops:
+		return typedOp.Idempotent()
+
+	default:
+		return op
+	}
+}
+
+// opPath returns path of an operation if it has one
+func opPath(op Op) (Pointer, bool) {
+	switch typedOp := op.(type) {
+	case ReplaceOp:
+		return typedOp.Path, true
+	case RemoveOp:
+		return typedOp.Path, true
+	case TestOp:
+		return typedOp.Path, true
+	case FindOp:
+		return typedOp.Path, true
+	case MergeOp:
+		return typedOp.Path, true
+	case StrategicMergeOp:
+		return typedOp.Path, true
+	case GroupOp:
+		return typedOp.Path, true
+	case ValidateOp:
+		return typedOp.Path, true
+	case DescriptiveOp:
+		return opPath(typedOp.Op)
+	default:
+		return Pointer{}, false
+	}
+}
+
+func applyToClone(op Op, doc interface{}) (interface{}, error) {
+	clonedDoc, err := cloneDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Apply(clonedDoc)
+}
+
+// cloneDoc deep copies document since operations modify it in place
+func cloneDoc(doc interface{}) (interface{}, error) {
+	clonedDoc, err := ReplaceOp{}.cloneValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("Cloning document: %s", err)
+	}
+
+	return clonedDoc, nil
+}
@@ -0,0 +1,144 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("CheckIdempotence", func() {
+	var doc interface{}
+
+	BeforeEach(func() {
+		doc = map[interface{}]interface{}{
+			"azs":  []interface{}{"z1"},
+			"jobs": []interface{}{map[interface{}]interface{}{"name": "a"}},
+		}
+	})
+
+	It("returns no violations for idempotent operations", func() {
+		violations, err := CheckIdempotence(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/azs/0"), Value: "z2"},
+			ReplaceOp{Path: MustNewPointerFromString("/jobs/name=b?/x"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/azs/=z3?"), Value: "z3"},
+			RemoveOp{Path: MustNewPointerFromString("/jobs/name=a?")},
+		}, doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(BeEmpty())
+	})
+
+	It("reports appends and inserts", func() {
+		violations, err := CheckIdempotence(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/azs/0"), Value: "z2"},
+			ReplaceOp{Path: MustNewPointerFromString("/azs/-"), Value: "z3"},
+			ReplaceOp{Path: MustNewPointerFromString("/jobs/name=a:after"), Value: "b"},
+			DescriptiveOp{Op: ReplaceOp{Path: MustNewPointerFromString("/azs/0:before"), Value: "z0"}, ErrorMsg: "desc"},
+		}, doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(Equal([]IdempotenceViolation{
+			{Index: 1, Path: MustNewPointerFromString("/azs/-")},
+			{Index: 2, Path: MustNewPointerFromString("/jobs/name=a:after")},
+			{Index: 3, Path: MustNewPointerFromString("/azs/0:before")},
+		}))
+
+		Expect(violations[0].String()).To(Equal("Operation [1] for path '/azs/-' changes document when applied again"))
+	})
+
+	It("reports operations that fail when applied again", func() {
+		violations, err := CheckIdempotence(Ops{
+			TestOp{Path: MustNewPointerFromString("/new"), Absent: true},
+			ReplaceOp{Path: MustNewPointerFromString("/new?"), Value: 1},
+		}, doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Index).To(Equal(0))
+		Expect(violations[0].String()).To(Equal(
+			"Operation [0] for path '/new' fails when applied again: Expected to not find '/new'"))
+	})
+
+	It("reports operations that interfere with each other", func() {
+		violations, err := CheckIdempotence(Ops{
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/deployed"), Absent: true},
+				Then: Ops{ReplaceOp{Path: MustNewPointerFromString("/mode?"), Value: "install"}},
+				Else: Ops{ReplaceOp{Path: MustNewPointerFromString("/mode?"), Value: "upgrade"}},
+			},
+			ReplaceOp{Path: MustNewPointerFromString("/deployed?"), Value: true},
+		}, doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(Equal([]IdempotenceViolation{{Index: 0}}))
+		Expect(violations[0].String()).To(Equal("Operation [0] changes document when applied again"))
+	})
+
+	It("reports operations that cannot be applied again to their own result", func() {
+		violations, err := CheckIdempotence(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/azs/=z1"), Value: "z2"},
+		}, doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].String()).To(Equal("Operation [0] for path '/azs/=z1' fails when applied again: " +
+			"Expected to find exactly one matching array item for path '/azs/=z1' but found 0"))
+	})
+
+	It("does not modify document", func() {
+		_, err := CheckIdempotence(Ops{ReplaceOp{Path: MustNewPointerFromString("/azs/-"), Value: "z3"}}, doc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(doc.(map[interface{}]interface{})["azs"]).To(Equal([]interface{}{"z1"}))
+	})
+
+	It("returns an error if operations cannot be applied", func() {
+		_, err := CheckIdempotence(Ops{RemoveOp{Path: MustNewPointerFromString("/missing")}}, doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Operation [0]: Expected to find a map key 'missing' for path '/missing' (found map keys: 'azs', 'jobs')"))
+	})
+})
+
+var _ = Describe("Ops.Idempotent", func() {
+	It("skips appending and inserting values that are already present", func() {
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/azs/-"), Value: "z2"},
+			ReplaceOp{Path: MustNewPointerFromString("/azs/0:before"), Value: "z0"},
+			DescriptiveOp{Op: ReplaceOp{Path: MustNewPointerFromString("/jobs/name=a:after"), Value: map[interface{}]interface{}{"name": "b"}}},
+			GroupOp{Path: MustNewPointerFromString("/jobs/name=a"), Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/azs?/-"), Value: "z1"},
+			}},
+		}.Idempotent()
+
+		doc := map[interface{}]interface{}{
+			"azs":  []interface{}{"z1"},
+			"jobs": []interface{}{map[interface{}]interface{}{"name": "a"}},
+		}
+
+		expectedDoc := map[interface{}]interface{}{
+			"azs": []interface{}{"z0", "z1", "z2"},
+			"jobs": []interface{}{
+				map[interface{}]interface{}{"name": "a", "azs": []interface{}{"z1"}},
+				map[interface{}]interface{}{"name": "b"},
+			},
+		}
+
+		res, err := ops.Apply(doc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(expectedDoc))
+
+		res, err = ops.Apply(res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(expectedDoc))
+
+		violations, err := CheckIdempotence(ops, doc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(BeEmpty())
+	})
+
+	It("still replaces existing array items", func() {
+		res, err := Ops{ReplaceOp{Path: MustNewPointerFromString("/1"), Value: "a"}}.Idempotent().Apply([]interface{}{"a", "b"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal([]interface{}{"a", "a"}))
+	})
+})
@@ -0,0 +1,44 @@
+package patch
+
+import (
+	"context"
+	"errors"
+)
+
+// IfOp applies Then operations if Test succeeds, otherwise Else operations.
+// Only failed assertions are treated as a failed condition; other errors
+// (e.g. missing parent or type mismatch) are returned.
+type IfOp struct {
+	Test TestOp
+	Then Ops
+	Else Ops
+}
+
+func (op IfOp) Apply(doc interface{}) (interface{}, error) {
+	return op.ApplyContext(context.Background(), doc)
+}
+
+func (op IfOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	_, err := applyContext(ctx, op.Test, doc)
+	if err != nil {
+		// cancellation is not a failed condition
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !op.failed(err) {
+			return nil, err
+		}
+		return op.Else.ApplyContext(ctx, doc)
+	}
+
+	return op.Then.ApplyContext(ctx, doc)
+}
+
+func (IfOp) failed(err error) bool {
+	for _, code := range []ErrCode{ErrCodeTestValueMismatch, ErrCodeTestUnexpectedValue, ErrCodeTestMissingValue, ErrCodeTestAssertion} {
+		if errors.Is(err, code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("IfOp.Apply", func() {
+	var (
+		setA = Ops{ReplaceOp{Path: MustNewPointerFromString("/a?"), Value: "then"}}
+		setB = Ops{ReplaceOp{Path: MustNewPointerFromString("/b?"), Value: "else"}}
+	)
+
+	It("applies then operations if test succeeds", func() {
+		res, err := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/x"), Value: 1},
+			Then: setA,
+			Else: setB,
+		}.Apply(map[interface{}]interface{}{"x": 1})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"x": 1, "a": "then"}))
+	})
+
+	It("applies else operations if test does not match", func() {
+		res, err := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/x"), Value: 2},
+			Then: setA,
+			Else: setB,
+		}.Apply(map[interface{}]interface{}{"x": 1})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"x": 1, "b": "else"}))
+	})
+
+	It("applies else operations if tested optional path cannot be found", func() {
+		res, err := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/items/name=missing?/x"), Value: 1},
+			Then: setA,
+			Else: setB,
+		}.Apply(map[interface{}]interface{}{"items": []interface{}{}})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"items": []interface{}{}, "b": "else"}))
+	})
+
+	It("supports absence checks", func() {
+		op := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/items/name=api"), Absent: true},
+			Else: Ops{ReplaceOp{Path: MustNewPointerFromString("/items/name=api/instances?"), Value: 2}},
+		}
+
+		res, err := op.Apply(map[interface{}]interface{}{"items": []interface{}{}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"items": []interface{}{}}))
+
+		res, err = op.Apply(map[interface{}]interface{}{
+			"items": []interface{}{map[interface{}]interface{}{"name": "api"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"items": []interface{}{map[interface{}]interface{}{"name": "api", "instances": 2}},
+		}))
+	})
+
+	It("returns original document if there are no operations for the outcome", func() {
+		res, err := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/x"), Value: 2},
+			Then: setA,
+		}.Apply(map[interface{}]interface{}{"x": 1})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"x": 1}))
+	})
+
+	It("returns an error if test cannot be evaluated", func() {
+		_, err := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/items/name=missing/x"), Value: 1},
+			Else: setB,
+		}.Apply(map[interface{}]interface{}{"items": []interface{}{}})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find exactly one matching array item for path '/items/name=missing' but found 0"))
+
+		_, err = IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/x/0"), Value: 1},
+			Else: setB,
+		}.Apply(map[interface{}]interface{}{"x": 1})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(OpMismatchTypeErr{}))
+	})
+
+	It("returns an error if chosen operations fail", func() {
+		_, err := IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/x"), Value: 1},
+			Then: Ops{RemoveOp{Path: MustNewPointerFromString("/y")}},
+		}.Apply(map[interface{}]interface{}{"x": 1})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find a map key 'y' for path '/y' (found map keys: 'x')"))
+	})
+})
@@ -0,0 +1,332 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	interpolationRegex         = regexp.MustCompile(`\(\(([-/\.\w\pL]+)\)\)`)
+	interpolationAnchoredRegex = regexp.MustCompile(`\A` + interpolationRegex.String() + `\z`)
+)
+
+// Variables provides values for ((var)) placeholders
+type Variables interface {
+	Get(name string) (interface{}, bool, error)
+}
+
+type StaticVariables map[string]interface{}
+
+var _ Variables = StaticVariables{}
+
+func (v StaticVariables) Get(name string) (interface{}, bool, error) {
+	val, found := v[name]
+	return val, found, nil
+}
+
+type VariablesMissingErr struct {
+	Names []string
+}
+
+func (e VariablesMissingErr) Error() string {
+	return fmt.Sprintf("Expected to find variables: %s", strings.Join(e.Names, ", "))
+}
+
+// Interpolator substitutes ((var)) and ((var.subkey)) placeholders
+type Interpolator struct {
+	Vars Variables
+
+	// AllowMissing leaves placeholders of missing variables as is
+	AllowMissing bool
+}
+
+func (i Interpolator) Interpolate(obj interface{}) (interface{}, error) {
+	tracker := newVarsTracker(i.Vars)
+
+	result, err := tracker.interpolate(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, i.missingErr(tracker)
+}
+
+func (i Interpolator) InterpolateOpDefinitions(opDefs []OpDefinition) ([]OpDefinition, error) {
+	tracker := newVarsTracker(i.Vars)
+
+	result, err := tracker.interpolateOpDefinitions(opDefs)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, i.missingErr(tracker)
+}
+
+func (i Interpolator) missingErr(tracker *varsTracker) error {
+	if i.AllowMissing {
+		return nil
+	}
+	return tracker.missingErr()
+}
+
+type varsTracker struct {
+	vars    Variables
+	missing map[string]struct{}
+}
+
+func newVarsTracker(vars Variables) *varsTracker {
+	if vars == nil {
+		vars = StaticVariables{}
+	}
+	return &varsTracker{vars: vars, missing: map[string]struct{}{}}
+}
+
+func (t *varsTracker) interpolateOpDefinitions(opDefs []OpDefinition) ([]OpDefinition, error) {
+	result := []OpDefinition{}
+
+	for i, opDef := range opDefs {
+		if opDef.Path != nil {
+			path, err := t.interpolatePath(*opDef.Path)
+			if err != nil {
+				return nil, fmt.Errorf("Operation [%d]: %s", i, err)
+			}
+			opDef.Path = &path
+		}
+
+		if opDef.Value != nil {
+			val, err := t.interpolate(*opDef.Value)
+			if err != nil {
+				return nil, fmt.Errorf("Operation [%d]: %s", i, err)
+			}
+			opDef.Value = &val
+		}
+
+		if opDef.Extra != nil {
+			extra := map[string]interface{}{}
+			for key, val := range opDef.Extra {
+				interpolated, err := t.interpolate(val)
+				if err != nil {
+					return nil, fmt.Errorf("Operation [%d]: %s: %s", i, key, err)
+				}
+				extra[key] = interpolated
+			}
+			opDef.Extra = extra
+		}
+
+		if opDef.Test != nil {
+			test, err := t.interpolateOpDefinitions([]OpDefinition{*opDef.Test})
+			if err != nil {
+				return nil, fmt.Errorf("Operation [%d]: Test: %s", i, err)
+			}
+			opDef.Test = &test[0]
+		}
+
+		if opDef.Ops != nil {
+			ops, err := t.interpolateOpDefinitions(opDef.Ops)
+			if err != nil {
+				return nil, fmt.Errorf("Operation [%d]: Ops: %s", i, err)
+			}
+			opDef.Ops = ops
+		}
+
+		if opDef.Else != nil {
+			elseOps, err := t.interpolateOpDefinitions(opDef.Else)
+			if err != nil {
+				return nil, fmt.Errorf("Operation [%d]: Else: %s", i, err)
+			}
+			opDef.Else = elseOps
+		}
+
+		result = append(result, opDef)
+	}
+
+	return result, nil
+}
+
+// interpolatePath substitutes placeholders within a pointer string;
+// substituted values are escaped so that they always form part of a single token
+func (t *varsTracker) interpolatePath(path string) (string, error) {
+	return t.interpolateEmbedded(path, "path", rfc6901Encoder.Replace)
+}
+
+func (t *varsTracker) interpolate(obj interface{}) (interface{}, error) {
+	switch typedObj := obj.(type) {
+	case map[interface{}]interface{}:
+		result := map[interface{}]interface{}{}
+
+		for k, v := range typedObj {
+			newK, err := t.interpolate(k)
+			if err != nil {
+				return nil, err
+			}
+
+			// maps and slices cannot be used as map keys
+			if newK != nil && !reflect.TypeOf(newK).Comparable() {
+				return nil, fmt.Errorf("Expected map key '%v' to interpolate to a string or a number but found '%T'", k, newK)
+			}
+
+			newV, err := t.interpolate(v)
+			if err != nil {
+				return nil, err
+			}
+
+			result[newK] = newV
+		}
+
+		return result, nil
+
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+
+		for k, v := range typedObj {
+			newK, err := t.interpolateString(k)
+			if err != nil {
+				return nil, err
+			}
+
+			strK, ok := newK.(string)
+			if !ok {
+				return nil, fmt.Errorf("Expected map key '%s' to interpolate to a string but found '%T'", k, newK)
+			}
+
+			newV, err := t.interpolate(v)
+			if err != nil {
+				return nil, err
+			}
+
+			result[strK] = newV
+		}
+
+		return result, nil
+
+	case []interface{}:
+		result := []interface{}{}
+
+		for _, v := range typedObj {
+			newV, err := t.interpolate(v)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, newV)
+		}
+
+		return result, nil
+
+	case string:
+		return t.interpolateString(typedObj)
+
+	default:
+		return obj, nil
+	}
+}
+
+func (t *varsTracker) interpolateString(str string) (interface{}, error) {
+	// Entire string is a placeholder, hence value keeps its type
+	if m := interpolationAnchoredRegex.FindStringSubmatch(str); m != nil {
+		val, found, err := t.get(m[1])
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return str, nil
+		}
+		return val, nil
+	}
+
+	return t.interpolateEmbedded(str, "string interpolation", func(s string) string { return s })
+}
+
+func (t *varsTracker) interpolateEmbedded(str, usage string, encode func(string) string) (string, error) {
+	var lastErr error
+
+	result := interpolationRegex.ReplaceAllStringFunc(str, func(match string) string {
+		name := interpolationRegex.FindStringSubmatch(match)[1]
+
+		val, found, err := t.get(name)
+		if err != nil {
+			lastErr = err
+			return match
+		}
+		if !found {
+			return match
+		}
+
+		switch val.(type) {
+		case map[interface{}]interface{}, map[string]interface{}, []interface{}:
+			lastErr = fmt.Errorf("Expected variable '%s' used in %s to be a scalar but found '%T'", name, usage, val)
+			return match
+		}
+
+		return encode(fmt.Sprintf("%v", val))
+	})
+
+	return result, lastErr
+}
+
+// get resolves 'name' or 'name.subkey.subkey2' and records missing variables
+func (t *varsTracker) get(fullName string) (interface{}, bool, error) {
+	pieces := strings.Split(fullName, ".")
+
+	val, found, err := t.vars.Get(pieces[0])
+	if err != nil {
+		return nil, false, fmt.Errorf("Getting variable '%s': %s", pieces[0], err)
+	}
+
+	if found {
+		for _, piece := range pieces[1:] {
+			ptr := dereference(reflect.ValueOf(val))
+			if ptr.Kind() != reflect.Map {
+				return nil, false, fmt.Errorf("Expected variable '%s' to be a map to find key '%s' but found '%T'", fullName, piece, val)
+			}
+
+			mapValue := t.mapIndex(ptr, piece)
+			if !mapValue.IsValid() {
+				found = false
+				break
+			}
+
+			val = mapValue.Interface()
+		}
+	}
+
+	if !found {
+		t.missing[fullName] = struct{}{}
+	}
+
+	return val, found, nil
+}
+
+// mapIndex compares keys by their string representation since YAML maps may have non-string keys
+func (t *varsTracker) mapIndex(m reflect.Value, key string) reflect.Value {
+	if m.Type().Key().Kind() == reflect.String {
+		return m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+	}
+
+	iter := m.MapRange()
+	for iter.Next() {
+		if fmt.Sprintf("%v", iter.Key().Interface()) == key {
+			return iter.Value()
+		}
+	}
+
+	return reflect.Value{}
+}
+
+func (t *varsTracker) missingErr() error {
+	if len(t.missing) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range t.missing {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return VariablesMissingErr{names}
+}
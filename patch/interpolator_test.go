@@ -0,0 +1,213 @@
+package patch_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+type FakeVariables struct {
+	Err error
+}
+
+func (v FakeVariables) Get(name string) (interface{}, bool, error) {
+	return nil, false, v.Err
+}
+
+var _ = Describe("Interpolator", func() {
+	vars := StaticVariables{
+		"str":   "val",
+		"int":   123,
+		"slash": "a/b",
+		"map": map[interface{}]interface{}{
+			"key":    "sub-val",
+			"nested": map[interface{}]interface{}{"key": 456},
+		},
+		"ary": []interface{}{"z1", "z2"},
+	}
+
+	Describe("Interpolate", func() {
+		It("substitutes entire placeholders keeping value types", func() {
+			res, err := Interpolator{Vars: vars}.Interpolate(map[interface{}]interface{}{
+				"a": "((str))",
+				"b": []interface{}{"((int))", "((ary))"},
+				"c": "((map))",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{
+				"a": "val",
+				"b": []interface{}{123, []interface{}{"z1", "z2"}},
+				"c": map[interface{}]interface{}{
+					"key":    "sub-val",
+					"nested": map[interface{}]interface{}{"key": 456},
+				},
+			}))
+		})
+
+		It("substitutes placeholders within strings", func() {
+			res, err := Interpolator{Vars: vars}.Interpolate("prefix-((str))-((int))")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal("prefix-val-123"))
+		})
+
+		It("substitutes map keys", func() {
+			res, err := Interpolator{Vars: vars}.Interpolate(map[interface{}]interface{}{"((str))": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"val": 1}))
+
+			res, err = Interpolator{Vars: vars}.Interpolate(map[string]interface{}{"key-((str))": 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[string]interface{}{"key-val": 1}))
+		})
+
+		It("returns an error if map key is substituted with a map or an array", func() {
+			_, err := Interpolator{Vars: vars}.Interpolate(map[interface{}]interface{}{"((map))": 1})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected map key '((map))' to interpolate to a string or a number " +
+				"but found 'map[interface {}]interface {}'"))
+
+			_, err = Interpolator{Vars: vars}.Interpolate(map[interface{}]interface{}{"((ary))": 1})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("but found '[]interface {}'"))
+		})
+
+		It("supports subkeys", func() {
+			res, err := Interpolator{Vars: vars}.Interpolate([]interface{}{"((map.key))", "((map.nested.key))"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"sub-val", 456}))
+		})
+
+		It("supports subkeys of maps with non-string keys", func() {
+			vars := StaticVariables{
+				"map": map[interface{}]interface{}{1: "int-val", true: "bool-val", "key": "str-val"},
+			}
+
+			res, err := Interpolator{Vars: vars}.Interpolate([]interface{}{"((map.1))", "((map.true))", "((map.key))"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"int-val", "bool-val", "str-val"}))
+
+			_, err = Interpolator{Vars: vars}.Interpolate("((map.2))")
+			Expect(err).To(Equal(VariablesMissingErr{Names: []string{"map.2"}}))
+		})
+
+		It("does not modify original object", func() {
+			doc := map[interface{}]interface{}{"a": "((str))"}
+
+			_, err := Interpolator{Vars: vars}.Interpolate(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(doc).To(Equal(map[interface{}]interface{}{"a": "((str))"}))
+		})
+
+		It("leaves non-string values alone", func() {
+			res, err := Interpolator{Vars: vars}.Interpolate([]interface{}{1, true, nil})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{1, true, nil}))
+		})
+
+		It("returns an error listing all missing variables", func() {
+			_, err := Interpolator{Vars: vars}.Interpolate([]interface{}{"((b))", "x-((a))", "((map.missing))", "((a))"})
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(Equal(VariablesMissingErr{Names: []string{"a", "b", "map.missing"}}))
+			Expect(err.Error()).To(Equal("Expected to find variables: a, b, map.missing"))
+		})
+
+		It("leaves placeholders of missing variables if allowed", func() {
+			res, err := Interpolator{Vars: vars, AllowMissing: true}.Interpolate([]interface{}{"((a))", "x-((a))-((str))"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"((a))", "x-((a))-val"}))
+		})
+
+		It("returns an error if non-scalar value is used within a string", func() {
+			_, err := Interpolator{Vars: vars}.Interpolate("x-((ary))")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected variable 'ary' used in string interpolation to be a scalar but found '[]interface {}'"))
+		})
+
+		It("returns an error if subkey is requested from non-map variable", func() {
+			_, err := Interpolator{Vars: vars}.Interpolate("((str.key))")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected variable 'str.key' to be a map to find key 'key' but found 'string'"))
+		})
+
+		It("returns an error if variables cannot be retrieved", func() {
+			_, err := Interpolator{Vars: FakeVariables{Err: errors.New("fake-err")}}.Interpolate("((a))")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Getting variable 'a': fake-err"))
+		})
+	})
+
+	Describe("InterpolateOpDefinitions", func() {
+		It("substitutes placeholders in paths and values", func() {
+			path := "/instance_groups/name=((str))/((map.key))?"
+			var val interface{} = map[interface{}]interface{}{"x": "((int))"}
+
+			opDefs, err := Interpolator{Vars: vars}.InterpolateOpDefinitions([]OpDefinition{
+				{Type: "replace", Path: &path, Value: &val},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(*opDefs[0].Path).To(Equal("/instance_groups/name=val/sub-val?"))
+			Expect(*opDefs[0].Value).To(Equal(map[interface{}]interface{}{"x": 123}))
+
+			Expect(path).To(Equal("/instance_groups/name=((str))/((map.key))?"))
+		})
+
+		It("substitutes placeholders in nested operations", func() {
+			path := "/((str))"
+			var val interface{} = "((int))"
+
+			opDefs, err := Interpolator{Vars: vars}.InterpolateOpDefinitions([]OpDefinition{
+				{
+					Type: "if",
+					Test: &OpDefinition{Path: &path, Value: &val},
+					Ops:  []OpDefinition{{Type: "replace", Path: &path, Value: &val}},
+					Else: []OpDefinition{{Type: "remove", Path: &path}},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(*opDefs[0].Test.Path).To(Equal("/val"))
+			Expect(*opDefs[0].Test.Value).To(Equal(123))
+			Expect(*opDefs[0].Ops[0].Path).To(Equal("/val"))
+			Expect(*opDefs[0].Ops[0].Value).To(Equal(123))
+			Expect(*opDefs[0].Else[0].Path).To(Equal("/val"))
+		})
+
+		It("escapes values substituted in paths", func() {
+			path := "/((slash))"
+
+			opDefs, err := Interpolator{Vars: vars}.InterpolateOpDefinitions([]OpDefinition{{Type: "remove", Path: &path}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*opDefs[0].Path).To(Equal("/a~1b"))
+
+			ops, err := NewOpsFromDefinitions(opDefs)
+			Expect(err).ToNot(HaveOccurred())
+
+			res, err := ops.Apply(map[interface{}]interface{}{"a/b": 1, "c": 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"c": 2}))
+		})
+
+		It("returns an error if non-scalar value is used in path", func() {
+			path := "/((ary))"
+
+			_, err := Interpolator{Vars: vars}.InterpolateOpDefinitions([]OpDefinition{{Type: "remove", Path: &path}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Operation [0]: Expected variable 'ary' used in path to be a scalar but found '[]interface {}'"))
+		})
+
+		It("returns an error listing all missing variables", func() {
+			path := "/((a))"
+			var val interface{} = "((b))"
+
+			_, err := Interpolator{Vars: vars}.InterpolateOpDefinitions([]OpDefinition{
+				{Type: "replace", Path: &path, Value: &val},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find variables: a, b"))
+		})
+	})
+})
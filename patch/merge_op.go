@@ -0,0 +1,284 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MergeOp merges value into the document at a path. By default it follows
+// JSON Merge Patch (https://tools.ietf.org/html/rfc7396): maps are merged recursively,
+// null values remove keys, and all other values (including arrays) are replaced.
+type MergeOp struct {
+	Path  Pointer
+	Value interface{} // will be cloned using yaml library
+
+	// Arrays determines how arrays found on both sides are merged
+	Arrays MergeArrayStrategy
+
+	// Key is used to match array items with MergeArraysByKey (defaults to 'name')
+	Key string
+
+	// Strict returns an error if merged values conflict with existing values
+	Strict bool
+}
+
+type MergeArrayStrategy string
+
+const (
+	MergeArraysReplace MergeArrayStrategy = "replace" // default
+	MergeArraysAppend  MergeArrayStrategy = "append"
+	MergeArraysUnion   MergeArrayStrategy = "union" // appends items that are not present
+	MergeArraysByKey   MergeArrayStrategy = "merge" // merges maps with matching key, appends others
+)
+
+const defaultMergeKey = "name"
+
+func (op MergeOp) Apply(doc interface{}) (interface{}, error) {
+	return op.apply(context.Background(), doc)
+}
+
+func (op MergeOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	return op.apply(ctx, doc)
+}
+
+func (op MergeOp) apply(ctx context.Context, doc interface{}) (interface{}, error) {
+	// Ensure that value is not modified by future operations
+	clonedValue, err := ReplaceOp{}.cloneValue(op.Value)
+	if err != nil {
+		return nil, fmt.Errorf("MergeOp cloning value: %s", err)
+	}
+
+	m := &merger{arrays: op.Arrays, key: op.Key, strict: op.Strict}
+
+	if len(m.key) == 0 {
+		m.key = defaultMergeKey
+	}
+
+	switch m.arrays {
+	case "", MergeArraysReplace, MergeArraysAppend, MergeArraysUnion, MergeArraysByKey:
+	default:
+		return nil, fmt.Errorf("Expected to find one of the following array strategies: "+
+			"'replace', 'append', 'union', 'merge' but found '%s'", m.arrays)
+	}
+
+	target, err := FindOp{Path: op.Path}.ApplyContext(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := m.merge(target, clonedValue, op.Path.Tokens())
+
+	if len(m.conflicts) > 0 {
+		sort.SliceStable(m.conflicts, func(i, j int) bool {
+			return m.conflicts[i].String() < m.conflicts[j].String()
+		})
+		return nil, OpMergeConflictErr{op.Path, m.conflicts}
+	}
+
+	return ReplaceOp{Path: op.Path, Value: merged}.ApplyContext(ctx, doc)
+}
+
+type merger struct {
+	arrays MergeArrayStrategy
+	key    string
+	strict bool
+
+	conflicts []Pointer
+}
+
+func (m *merger) merge(target, patch interface{}, tokens []Token) interface{} {
+	switch typedPatch := patch.(type) {
+	case map[interface{}]interface{}:
+		result := map[interface{}]interface{}{}
+
+		if typedTarget, ok := target.(map[interface{}]interface{}); ok {
+			for k, v := range typedTarget {
+				result[k] = v
+			}
+		} else if target != nil {
+			m.conflict(tokens)
+		}
+
+		for k, v := range typedPatch {
+			newTokens := append(append([]Token{}, tokens...), KeyToken{Key: fmt.Sprintf("%v", k)})
+
+			if v == nil {
+				if _, found := result[k]; found {
+					m.conflict(newTokens)
+				}
+				delete(result, k)
+			} else {
+				result[k] = m.merge(result[k], v, newTokens)
+			}
+		}
+
+		return result
+
+	case []interface{}:
+		typedTarget, ok := target.([]interface{})
+		if !ok || m.arrays == "" || m.arrays == MergeArraysReplace {
+			if target != nil && !reflect.DeepEqual(target, patch) {
+				m.conflict(tokens)
+			}
+			return patch
+		}
+
+		result := append([]interface{}{}, typedTarget...)
+
+		for _, item := range typedPatch {
+			switch m.arrays {
+			case MergeArraysUnion:
+				if m.indexOf(result, item) == -1 {
+					result = append(result, item)
+				}
+
+			case MergeArraysByKey:
+				if idx, keyVal := m.indexOfKey(result, item); idx != -1 {
+					newTokens := append(append([]Token{}, tokens...), MatchingIndexToken{Key: m.key, Value: fmt.Sprintf("%v", keyVal)})
+					result[idx] = m.merge(result[idx], item, newTokens)
+				} else {
+					result = append(result, item)
+				}
+
+			default:
+				result = append(result, item)
+			}
+		}
+
+		return result
+
+	default:
+		if target != nil && !reflect.DeepEqual(target, patch) {
+			m.conflict(tokens)
+		}
+		return patch
+	}
+}
+
+func (m *merger) conflict(tokens []Token) {
+	if m.strict {
+		m.conflicts = append(m.conflicts, NewPointer(tokens))
+	}
+}
+
+func (merger) indexOf(items []interface{}, item interface{}) int {
+	for i, existingItem := range items {
+		if reflect.DeepEqual(existingItem, item) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m merger) indexOfKey(items []interface{}, item interface{}) (int, interface{}) {
+	typedItem, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return -1, nil
+	}
+
+	keyVal, found := typedItem[m.key]
+	if !found {
+		return -1, nil
+	}
+
+	for i, existingItem := range items {
+		if typedExistingItem, ok := existingItem.(map[interface{}]interface{}); ok {
+			if existingKeyVal, found := typedExistingItem[m.key]; found && reflect.DeepEqual(existingKeyVal, keyVal) {
+				return i, keyVal
+			}
+		}
+	}
+
+	return -1, nil
+}
+
+// CalculateMergePatch returns a JSON Merge Patch (https://tools.ietf.org/html/rfc7396)
+// that converts left document into right document. Since merge patches use null
+// to remove keys, null values within right document cannot be represented.
+// Ignore and Include patterns select map keys; other values (including arrays)
+// are replaced as a whole. Unchecked does not apply since there are no tests.
+func (d Diff) CalculateMergePatch() interface{} {
+	scope, skip := d.filter(d.rootScope(&[]Pointer{}), diffNode{RootToken{}, d.Left, d.Right, true, true})
+	if !skip {
+		if patch, changed := d.calculateMergePatch(d.Left, d.Right, scope); changed {
+			return patch
+		}
+	}
+
+	// keep left document as is
+	if _, ok := d.Left.(map[interface{}]interface{}); ok {
+		return map[interface{}]interface{}{}
+	}
+	return d.Left
+}
+
+// calculateMergePatch returns false if there are no changes within scope
+func (d Diff) calculateMergePatch(left, right interface{}, scope diffScope) (interface{}, bool) {
+	typedLeft, ok := left.(map[interface{}]interface{})
+	if !ok {
+		return right, scope.included
+	}
+
+	typedRight, ok := right.(map[interface{}]interface{})
+	if !ok {
+		return right, scope.included
+	}
+
+	patch := map[interface{}]interface{}{}
+
+	var allKeys []interface{}
+	for k := range typedLeft {
+		allKeys = append(allKeys, k)
+	}
+	for k := range typedRight {
+		if _, found := typedLeft[k]; !found {
+			allKeys = append(allKeys, k)
+		}
+	}
+
+	for _, k := range allKeys {
+		leftVal, leftFound := typedLeft[k]
+		rightVal, rightFound := typedRight[k]
+
+		childScope, skip := d.child(scope, diffNode{KeyToken{Key: fmt.Sprintf("%v", k)}, leftVal, rightVal, leftFound, rightFound})
+		if skip {
+			continue
+		}
+
+		switch {
+		case childScope.included && !rightFound:
+			patch[k] = nil
+		case childScope.included && !leftFound:
+			patch[k] = rightVal
+		case leftFound && rightFound && d.Equality.Equal(leftVal, rightVal):
+			// no changes
+		default:
+			// look for included keys within value found on one side only
+			if !leftFound {
+				leftVal = emptyLike(rightVal)
+			}
+			if !rightFound {
+				rightVal = emptyLike(leftVal)
+			}
+			if subPatch, changed := d.calculateMergePatch(leftVal, rightVal, childScope); changed {
+				patch[k] = subPatch
+			}
+		}
+	}
+
+	return patch, scope.included || len(patch) > 0
+}
+
+// emptyLike returns empty map or array matching value's type
+func emptyLike(val interface{}) interface{} {
+	switch val.(type) {
+	case map[interface{}]interface{}:
+		return map[interface{}]interface{}{}
+	case []interface{}:
+		return []interface{}{}
+	default:
+		return nil
+	}
+}
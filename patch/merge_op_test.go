@@ -0,0 +1,433 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("MergeOp.Apply", func() {
+	It("merges maps recursively, removes keys with null values and replaces other values", func() {
+		res, err := MergeOp{
+			Path: MustNewPointerFromString(""),
+			Value: map[interface{}]interface{}{
+				"a": "z",
+				"c": map[interface{}]interface{}{"f": nil},
+				"g": []interface{}{3},
+			},
+		}.Apply(map[interface{}]interface{}{
+			"a": "b",
+			"c": map[interface{}]interface{}{"d": "e", "f": "g"},
+			"g": []interface{}{1, 2},
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"a": "z",
+			"c": map[interface{}]interface{}{"d": "e"},
+			"g": []interface{}{3},
+		}))
+	})
+
+	It("merges at a path", func() {
+		res, err := MergeOp{
+			Path:  MustNewPointerFromString("/instance_groups/name=api/jobs/name=x/properties"),
+			Value: map[interface{}]interface{}{"a": 2, "b": nil, "c": map[interface{}]interface{}{"d": 3}},
+		}.Apply(map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{
+					"name": "api",
+					"jobs": []interface{}{
+						map[interface{}]interface{}{
+							"name":       "x",
+							"properties": map[interface{}]interface{}{"a": 1, "b": 1, "e": 1},
+						},
+					},
+				},
+			},
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{
+					"name": "api",
+					"jobs": []interface{}{
+						map[interface{}]interface{}{
+							"name":       "x",
+							"properties": map[interface{}]interface{}{"a": 2, "e": 1, "c": map[interface{}]interface{}{"d": 3}},
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("creates missing optional locations", func() {
+		res, err := MergeOp{
+			Path:  MustNewPointerFromString("/a?/b"),
+			Value: map[interface{}]interface{}{"c": 1, "d": nil},
+		}.Apply(map[interface{}]interface{}{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"a": map[interface{}]interface{}{"b": map[interface{}]interface{}{"c": 1}},
+		}))
+	})
+
+	It("replaces non-map targets", func() {
+		res, err := MergeOp{
+			Path:  MustNewPointerFromString("/a"),
+			Value: map[interface{}]interface{}{"b": 1},
+		}.Apply(map[interface{}]interface{}{"a": []interface{}{1}})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 1}}))
+
+		res, err = MergeOp{
+			Path:  MustNewPointerFromString("/a"),
+			Value: "str",
+		}.Apply(map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 1}})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"a": "str"}))
+	})
+
+	It("does not modify value for future operations", func() {
+		val := map[interface{}]interface{}{"b": map[interface{}]interface{}{"c": 1}}
+		op := MergeOp{Path: MustNewPointerFromString("/a"), Value: val}
+
+		res, err := op.Apply(map[interface{}]interface{}{"a": map[interface{}]interface{}{}})
+		Expect(err).ToNot(HaveOccurred())
+
+		res.(map[interface{}]interface{})["a"].(map[interface{}]interface{})["b"].(map[interface{}]interface{})["c"] = 2
+
+		Expect(val).To(Equal(map[interface{}]interface{}{"b": map[interface{}]interface{}{"c": 1}}))
+	})
+
+	It("returns an error if path cannot be found", func() {
+		_, err := MergeOp{
+			Path:  MustNewPointerFromString("/a/b"),
+			Value: map[interface{}]interface{}{},
+		}.Apply(map[interface{}]interface{}{})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find a map key 'a' for path '/a' (found no other map keys)"))
+	})
+
+	// Examples from https://tools.ietf.org/html/rfc7396#appendix-A
+	It("satisfies RFC examples", func() {
+		examples := [][]string{
+			{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+			{`{"a":"b"}`, `{"a":null}`, `{}`},
+			{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+			{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+			{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+			{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+			{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+			{`{"a":"b"}`, `["c"]`, `["c"]`},
+			{`{"a":"foo"}`, `null`, `null`},
+			{`{"a":"foo"}`, `"bar"`, `"bar"`},
+			{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+			{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+			{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		}
+
+		for _, example := range examples {
+			var target, patch, expected interface{}
+
+			Expect(yaml.Unmarshal([]byte(example[0]), &target)).To(Succeed())
+			Expect(yaml.Unmarshal([]byte(example[1]), &patch)).To(Succeed())
+			Expect(yaml.Unmarshal([]byte(example[2]), &expected)).To(Succeed())
+
+			res, err := MergeOp{Path: MustNewPointerFromString(""), Value: patch}.Apply(target)
+			Expect(err).ToNot(HaveOccurred())
+
+			if expected == nil {
+				Expect(res).To(BeNil())
+			} else {
+				Expect(res).To(Equal(expected), "example: %#v", example)
+			}
+		}
+	})
+})
+
+var _ = Describe("MergeOp.Apply with array strategies", func() {
+	var doc interface{}
+
+	BeforeEach(func() {
+		doc = map[interface{}]interface{}{
+			"azs": []interface{}{"z1", "z2"},
+			"jobs": []interface{}{
+				map[interface{}]interface{}{"name": "a", "properties": map[interface{}]interface{}{"x": 1, "z": "str"}},
+				map[interface{}]interface{}{"name": "b"},
+			},
+		}
+	})
+
+	It("replaces arrays by default", func() {
+		res, err := MergeOp{
+			Path:  MustNewPointerFromString(""),
+			Value: map[interface{}]interface{}{"azs": []interface{}{"z3"}},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(map[interface{}]interface{})["azs"]).To(Equal([]interface{}{"z3"}))
+	})
+
+	It("appends items", func() {
+		res, err := MergeOp{
+			Path:   MustNewPointerFromString(""),
+			Value:  map[interface{}]interface{}{"azs": []interface{}{"z2", "z3"}},
+			Arrays: MergeArraysAppend,
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(map[interface{}]interface{})["azs"]).To(Equal([]interface{}{"z1", "z2", "z2", "z3"}))
+	})
+
+	It("appends items that are not present", func() {
+		res, err := MergeOp{
+			Path:   MustNewPointerFromString(""),
+			Value:  map[interface{}]interface{}{"azs": []interface{}{"z2", "z3", "z3"}},
+			Arrays: MergeArraysUnion,
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(map[interface{}]interface{})["azs"]).To(Equal([]interface{}{"z1", "z2", "z3"}))
+	})
+
+	It("merges items with matching key and appends others", func() {
+		res, err := MergeOp{
+			Path: MustNewPointerFromString("/jobs"),
+			Value: []interface{}{
+				map[interface{}]interface{}{"name": "a", "properties": map[interface{}]interface{}{"y": 2}},
+				map[interface{}]interface{}{"name": "c"},
+				map[interface{}]interface{}{"no-name": true},
+			},
+			Arrays: MergeArraysByKey,
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(map[interface{}]interface{})["jobs"]).To(Equal([]interface{}{
+			map[interface{}]interface{}{"name": "a", "properties": map[interface{}]interface{}{"x": 1, "y": 2, "z": "str"}},
+			map[interface{}]interface{}{"name": "b"},
+			map[interface{}]interface{}{"name": "c"},
+			map[interface{}]interface{}{"no-name": true},
+		}))
+	})
+
+	It("merges items by custom key recursively", func() {
+		res, err := MergeOp{
+			Path: MustNewPointerFromString(""),
+			Value: map[interface{}]interface{}{
+				"items": []interface{}{
+					map[interface{}]interface{}{"id": 1, "tags": []interface{}{"b"}},
+				},
+			},
+			Arrays: MergeArraysByKey,
+			Key:    "id",
+		}.Apply(map[interface{}]interface{}{
+			"items": []interface{}{
+				map[interface{}]interface{}{"id": 1, "tags": []interface{}{"a"}},
+			},
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"items": []interface{}{
+				map[interface{}]interface{}{"id": 1, "tags": []interface{}{"a", "b"}},
+			},
+		}))
+	})
+
+	It("returns an error for unknown array strategy", func() {
+		_, err := MergeOp{
+			Path:   MustNewPointerFromString(""),
+			Value:  map[interface{}]interface{}{},
+			Arrays: "unknown",
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find one of the following array strategies: 'replace', 'append', 'union', 'merge' but found 'unknown'"))
+	})
+
+	Describe("strict", func() {
+		It("returns an error listing all conflicting paths", func() {
+			_, err := MergeOp{
+				Path: MustNewPointerFromString(""),
+				Value: map[interface{}]interface{}{
+					"azs": []interface{}{"z3"},
+					"jobs": []interface{}{
+						map[interface{}]interface{}{"name": "a", "properties": map[interface{}]interface{}{"x": 2, "y": 2, "z": []interface{}{}}},
+						map[interface{}]interface{}{"name": "b", "properties": "str"},
+					},
+				},
+				Arrays: MergeArraysByKey,
+				Strict: true,
+			}.Apply(doc)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(Equal(OpMergeConflictErr{
+				Path: MustNewPointerFromString(""),
+				Conflicts: []Pointer{
+					MustNewPointerFromString("/jobs/name=a/properties/x"),
+					MustNewPointerFromString("/jobs/name=a/properties/z"),
+				},
+			}))
+			Expect(err.Error()).To(Equal("Expected merged values to not conflict with existing values for path '' but found conflicts at: '/jobs/name=a/properties/x', '/jobs/name=a/properties/z'"))
+		})
+
+		It("reports conflicts within maps with non-string keys", func() {
+			_, err := MergeOp{
+				Path:   MustNewPointerFromString("/ports"),
+				Value:  map[interface{}]interface{}{80: "https"},
+				Strict: true,
+			}.Apply(map[interface{}]interface{}{"ports": map[interface{}]interface{}{80: "http"}})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected merged values to not conflict with existing values for path '/ports' but found conflicts at: '/ports/80'"))
+		})
+
+		It("reports type mismatches as conflicts", func() {
+			_, err := MergeOp{
+				Path:   MustNewPointerFromString("/azs"),
+				Value:  map[interface{}]interface{}{"a": 1},
+				Strict: true,
+			}.Apply(doc)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected merged values to not conflict with existing values for path '/azs' but found conflicts at: '/azs'"))
+		})
+
+		It("succeeds if values are same, new or removed but missing", func() {
+			res, err := MergeOp{
+				Path: MustNewPointerFromString(""),
+				Value: map[interface{}]interface{}{
+					"azs":     []interface{}{"z1", "z2"},
+					"missing": nil,
+					"new":     1,
+				},
+				Strict: true,
+			}.Apply(doc)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.(map[interface{}]interface{})["azs"]).To(Equal([]interface{}{"z1", "z2"}))
+			Expect(res.(map[interface{}]interface{})["new"]).To(Equal(1))
+			Expect(res.(map[interface{}]interface{})).ToNot(HaveKey("missing"))
+		})
+
+		It("reports removal of existing keys as conflicts", func() {
+			_, err := MergeOp{
+				Path:   MustNewPointerFromString(""),
+				Value:  map[interface{}]interface{}{"jobs": nil},
+				Strict: true,
+			}.Apply(doc)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected merged values to not conflict with existing values for path '' but found conflicts at: '/jobs'"))
+		})
+
+		It("does not report appended items as conflicts", func() {
+			res, err := MergeOp{
+				Path:   MustNewPointerFromString("/azs"),
+				Value:  []interface{}{"z3"},
+				Arrays: MergeArraysAppend,
+				Strict: true,
+			}.Apply(doc)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.(map[interface{}]interface{})["azs"]).To(Equal([]interface{}{"z1", "z2", "z3"}))
+		})
+	})
+})
+
+var _ = Describe("Diff.CalculateMergePatch", func() {
+	testMergePatch := func(left, right, expectedPatch interface{}) {
+		patch := Diff{Left: left, Right: right}.CalculateMergePatch()
+		Expect(patch).To(Equal(expectedPatch))
+
+		res, err := MergeOp{Path: MustNewPointerFromString(""), Value: patch}.Apply(left)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(right))
+	}
+
+	It("returns empty patch for same documents", func() {
+		testMergePatch(
+			map[interface{}]interface{}{"a": 1},
+			map[interface{}]interface{}{"a": 1},
+			map[interface{}]interface{}{},
+		)
+	})
+
+	It("returns changed, added and removed keys", func() {
+		testMergePatch(
+			map[interface{}]interface{}{
+				"a": 1,
+				"b": 2,
+				"c": map[interface{}]interface{}{"d": 3, "e": 4, "f": map[interface{}]interface{}{"g": 5}},
+				"h": []interface{}{1, 2},
+				"i": []interface{}{1},
+			},
+			map[interface{}]interface{}{
+				"a": 1,
+				"c": map[interface{}]interface{}{"d": 4, "f": map[interface{}]interface{}{"g": 5}},
+				"h": []interface{}{1},
+				"i": map[interface{}]interface{}{"j": 1},
+				"k": "new",
+			},
+			map[interface{}]interface{}{
+				"b": nil,
+				"c": map[interface{}]interface{}{"d": 4, "e": nil},
+				"h": []interface{}{1},
+				"i": map[interface{}]interface{}{"j": 1},
+				"k": "new",
+			},
+		)
+	})
+
+	It("returns right document if either document is not a map", func() {
+		testMergePatch([]interface{}{1}, []interface{}{2}, []interface{}{2})
+		testMergePatch("a", map[interface{}]interface{}{"a": 1}, map[interface{}]interface{}{"a": 1})
+		testMergePatch(map[interface{}]interface{}{"a": 1}, "a", "a")
+	})
+	It("only returns changes selected by ignore and include patterns", func() {
+		left := map[interface{}]interface{}{
+			"a": map[interface{}]interface{}{"b": 1, "c": 2},
+			"d": map[interface{}]interface{}{"b": 1, "c": 2},
+			"e": []interface{}{1},
+		}
+		right := map[interface{}]interface{}{
+			"a": map[interface{}]interface{}{"b": 2, "c": 3},
+			"e": []interface{}{2},
+			"f": map[interface{}]interface{}{"b": 1, "c": 2},
+		}
+
+		patch := Diff{
+			Left:    left,
+			Right:   right,
+			Ignore:  []Pointer{MustNewPointerFromString("/e")},
+			Include: []Pointer{MustNewPointerFromString("/*/b"), MustNewPointerFromString("/e")},
+		}.CalculateMergePatch()
+
+		Expect(patch).To(Equal(map[interface{}]interface{}{
+			"a": map[interface{}]interface{}{"b": 2},
+			"d": map[interface{}]interface{}{"b": nil},
+			"f": map[interface{}]interface{}{"b": 1},
+		}))
+	})
+
+	It("does not change document if all changes are skipped", func() {
+		diff := Diff{Ignore: []Pointer{MustNewPointerFromString("")}}
+
+		diff.Left, diff.Right = map[interface{}]interface{}{"a": 1}, map[interface{}]interface{}{"a": 2}
+		Expect(diff.CalculateMergePatch()).To(Equal(map[interface{}]interface{}{}))
+
+		diff.Left, diff.Right = "a", "b"
+		Expect(diff.CalculateMergePatch()).To(Equal("a"))
+	})
+})
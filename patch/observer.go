@@ -0,0 +1,91 @@
+package patch
+
+import (
+	"context"
+	"time"
+)
+
+// OpEvent describes application of a single operation
+type OpEvent struct {
+	Index    int
+	Op       Op
+	Path     Pointer       // resolved against the document when possible; not set for operations without a path
+	Duration time.Duration // only set after operation is applied
+	Err      error         // only set after operation is applied
+}
+
+// OpObserver is notified before and after each operation is applied
+type OpObserver interface {
+	BeforeOp(OpEvent)
+	AfterOp(OpEvent)
+}
+
+// OpObserverFuncs allows to use functions as an observer; nil functions are skipped
+type OpObserverFuncs struct {
+	Before func(OpEvent)
+	After  func(OpEvent)
+}
+
+func (f OpObserverFuncs) BeforeOp(event OpEvent) {
+	if f.Before != nil {
+		f.Before(event)
+	}
+}
+
+func (f OpObserverFuncs) AfterOp(event OpEvent) {
+	if f.After != nil {
+		f.After(event)
+	}
+}
+
+// ObservedOps applies operations notifying observers in order
+type ObservedOps struct {
+	Ops       Ops
+	Observers []OpObserver
+}
+
+// Observe returns operations that notify observers when applied
+func (ops Ops) Observe(observers ...OpObserver) ObservedOps {
+	return ObservedOps{Ops: ops, Observers: observers}
+}
+
+func (o ObservedOps) Apply(doc interface{}) (interface{}, error) {
+	return o.ApplyContext(context.Background(), doc)
+}
+
+func (o ObservedOps) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	var err error
+
+	for i, op := range o.Ops {
+		event := OpEvent{Index: i, Op: op}
+
+		if path, found := opPath(op); found {
+			event.Path = path
+
+			if loc, err := (explainer{}).locate(doc, path); err == nil {
+				event.Path = loc.path
+			}
+		}
+
+		for _, observer := range o.Observers {
+			observer.BeforeOp(event)
+		}
+
+		started := time.Now()
+
+		doc, err = applyContext(ctx, op, doc)
+
+		event.Duration = time.Since(started)
+		event.Err = err
+
+		for _, observer := range o.Observers {
+			observer.AfterOp(event)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
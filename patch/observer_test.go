@@ -0,0 +1,95 @@
+package patch_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+type recordingObserver struct {
+	events *[]string
+	after  *[]OpEvent
+}
+
+func (o recordingObserver) BeforeOp(event OpEvent) {
+	*o.events = append(*o.events, "before "+event.Path.String())
+}
+
+func (o recordingObserver) AfterOp(event OpEvent) {
+	*o.events = append(*o.events, "after "+event.Path.String())
+	*o.after = append(*o.after, event)
+}
+
+var _ = Describe("ObservedOps", func() {
+	var (
+		events   []string
+		after    []OpEvent
+		observer recordingObserver
+		doc      interface{}
+	)
+
+	BeforeEach(func() {
+		events = nil
+		after = nil
+		observer = recordingObserver{&events, &after}
+		doc = map[interface{}]interface{}{
+			"azs": []interface{}{"z1"},
+		}
+	})
+
+	It("notifies observers before and after each operation with resolved paths", func() {
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/azs/=z1"), Value: "z2"},
+			DescriptiveOp{Op: ReplaceOp{Path: MustNewPointerFromString("/azs/-"), Value: "z3"}, ErrorMsg: "desc"},
+			IfOp{Test: TestOp{Path: MustNewPointerFromString("/azs"), Absent: true}},
+		}
+
+		var indexes []int
+
+		res, err := ops.Observe(observer, OpObserverFuncs{
+			After: func(event OpEvent) { indexes = append(indexes, event.Index) },
+		}).Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"azs": []interface{}{"z2", "z3"}}))
+
+		Expect(events).To(Equal([]string{
+			"before /azs/0", "after /azs/0",
+			"before /azs/1", "after /azs/1",
+			"before ", "after ",
+		}))
+
+		Expect(indexes).To(Equal([]int{0, 1, 2}))
+
+		Expect(after).To(HaveLen(3))
+		Expect(after[1].Op).To(Equal(ops[1]))
+		Expect(after[1].Err).ToNot(HaveOccurred())
+		Expect(after[1].Duration).To(BeNumerically(">", 0))
+	})
+
+	It("notifies observers about failed operation and stops", func() {
+		ops := Ops{
+			RemoveOp{Path: MustNewPointerFromString("/missing")},
+			ReplaceOp{Path: MustNewPointerFromString("/azs/0"), Value: "z2"},
+		}
+
+		_, err := ops.Observe(observer).Apply(doc)
+		Expect(err).To(HaveOccurred())
+
+		Expect(events).To(Equal([]string{"before /missing", "after /missing"}))
+		Expect(after[0].Err).To(Equal(err))
+	})
+
+	It("reports unresolved paths as is", func() {
+		_, err := Ops{ErrOp{Err: errors.New("fail")}}.Observe(observer).Apply(doc)
+		Expect(err).To(HaveOccurred())
+
+		_, err = Ops{TestOp{Path: MustNewPointerFromString("/azs/5"), Absent: true}}.Observe(observer).Apply(doc)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(events).To(Equal([]string{"before ", "after ", "before /azs/5", "after /azs/5"}))
+	})
+})
@@ -3,6 +3,8 @@ package patch
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -13,45 +15,140 @@ type OpDefinition struct {
 	Value  *interface{} `json:",omitempty" yaml:",omitempty"`
 	Absent *bool        `json:",omitempty" yaml:",omitempty"`
 	Error  *string      `json:",omitempty" yaml:",omitempty"`
+
+	// Replace operations
+	Idempotent *bool `json:",omitempty" yaml:",omitempty"`
+
+	// Test operations
+	Exists   *bool         `json:",omitempty" yaml:",omitempty"`
+	Is       *string       `json:",omitempty" yaml:",omitempty"`
+	Matches  *string       `json:",omitempty" yaml:",omitempty"`
+	Min      *float64      `json:",omitempty" yaml:",omitempty"`
+	Max      *float64      `json:",omitempty" yaml:",omitempty"`
+	Contains []interface{} `json:",omitempty" yaml:",omitempty"`
+	Keys     []string      `json:",omitempty" yaml:",omitempty"`
+	Length   *int          `json:",omitempty" yaml:",omitempty"`
+
+	// Test operations equality (see Equality)
+	NormalizeNumbers *bool `json:",omitempty" yaml:",omitempty"`
+	NullAsAbsent     *bool `json:",omitempty" yaml:",omitempty"`
+	IgnoreEmpty      *bool `json:",omitempty" yaml:",omitempty"`
+
+	// Conditional and group operations
+	Test *OpDefinition  `json:",omitempty" yaml:",omitempty"`
+	Ops  []OpDefinition `json:",omitempty" yaml:",omitempty"`
+	Else []OpDefinition `json:",omitempty" yaml:",omitempty"`
+
+	// Merge operations
+	Arrays *string `json:",omitempty" yaml:",omitempty"`
+	Key    *string `json:",omitempty" yaml:",omitempty"`
+	Strict *bool   `json:",omitempty" yaml:",omitempty"`
+
+	// Strategic merge operations
+	MergeKeys map[string]string `json:",omitempty" yaml:",omitempty"`
+
+	// Validate operations
+	Schema *string `json:",omitempty" yaml:",omitempty"` // path to JSON Schema file
+
+	// Custom operations (see RegisterOpType)
+	Extra map[string]interface{} `json:"-" yaml:",inline"` // fields not known to built-in operations
 }
 
-type parser struct{}
+// opDefinitionFields avoids recursion when marshaling definitions with extra fields
+type opDefinitionFields OpDefinition
 
-func NewOpsFromDefinitions(opDefs []OpDefinition) (Ops, error) {
-	var ops []Op
-	var p parser
+func (d OpDefinition) MarshalJSON() ([]byte, error) {
+	bytes, err := json.Marshal(opDefinitionFields(d))
+	if err != nil || len(d.Extra) == 0 {
+		return bytes, err
+	}
 
-	for i, opDef := range opDefs {
-		var op Op
-		var err error
+	var keys []string
+	for key := range d.Extra {
+		if !isOpDefinitionField(key) {
+			keys = append(keys, key)
+		}
+	}
 
-		opFmt := p.fmtOpDef(opDef)
+	sort.Strings(keys)
 
-		switch opDef.Type {
-		case "replace":
-			op, err = p.newReplaceOp(opDef)
-			if err != nil {
-				return nil, fmt.Errorf("Replace operation [%d]: %s within\n%s", i, err, opFmt)
-			}
+	// extra fields follow known fields
+	bytes = bytes[:len(bytes)-1]
 
-		case "remove":
-			op, err = p.newRemoveOp(opDef)
-			if err != nil {
-				return nil, fmt.Errorf("Remove operation [%d]: %s within\n%s", i, err, opFmt)
-			}
+	for _, key := range keys {
+		keyBytes, _ := json.Marshal(key)
 
-		case "test":
-			op, err = p.newTestOp(opDef)
-			if err != nil {
-				return nil, fmt.Errorf("Test operation [%d]: %s within\n%s", i, err, opFmt)
-			}
+		valBytes, err := json.Marshal(jsonValue(d.Extra[key]))
+		if err != nil {
+			return nil, err
+		}
 
-		default:
-			return nil, fmt.Errorf("Unknown operation [%d] with type '%s' within\n%s", i, opDef.Type, opFmt)
+		if len(bytes) > 1 {
+			bytes = append(bytes, ',')
 		}
 
-		if opDef.Error != nil {
-			op = DescriptiveOp{Op: op, ErrorMsg: *opDef.Error}
+		bytes = append(append(append(bytes, keyBytes...), ':'), valBytes...)
+	}
+
+	return append(bytes, '}'), nil
+}
+
+func (d *OpDefinition) UnmarshalJSON(bytes []byte) error {
+	var def opDefinitionFields
+
+	err := json.Unmarshal(bytes, &def)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+
+	err = json.Unmarshal(bytes, &fields)
+	if err != nil {
+		return err
+	}
+
+	for key, val := range fields {
+		if !isOpDefinitionField(key) {
+			if def.Extra == nil {
+				def.Extra = map[string]interface{}{}
+			}
+			def.Extra[key] = val
+		}
+	}
+
+	*d = OpDefinition(def)
+
+	return nil
+}
+
+type parser struct {
+	loadSchema SchemaLoader // nil if schemas of validate operations cannot be loaded
+}
+
+// NewOpsFromDefinitions does not read any files; see NewOpsFromDefinitionsWithSchemaLoader for validate operations
+func NewOpsFromDefinitions(opDefs []OpDefinition) (Ops, error) {
+	return parser{}.newOps(opDefs)
+}
+
+// NewOpsFromDefinitionsWithSchemaLoader loads schemas of validate operations via loader (ex: NewSchemaDirLoader)
+func NewOpsFromDefinitionsWithSchemaLoader(opDefs []OpDefinition, loader SchemaLoader) (Ops, error) {
+	return parser{loadSchema: loader}.newOps(opDefs)
+}
+
+func (p parser) newOps(opDefs []OpDefinition) (Ops, error) {
+	var ops []Op
+
+	for i, opDef := range opDefs {
+		op, kind, err := p.newOp(opDef)
+		if err != nil {
+			opFmt := p.fmtOpDef(opDef)
+
+			if len(kind) == 0 {
+				return nil, fmt.Errorf("Unknown operation [%d] with type '%s' within\n%s", i, opDef.Type, opFmt)
+			}
+
+			return nil, fmt.Errorf("%s operation [%d]: %s within\n%s", kind, i, err, opFmt)
 		}
 
 		ops = append(ops, op)
@@ -60,6 +157,67 @@ func NewOpsFromDefinitions(opDefs []OpDefinition) (Ops, error) {
 	return Ops(ops), nil
 }
 
+// newOp returns operation along with its kind used in error messages;
+// kind is empty for unknown operation types
+func (p parser) newOp(opDef OpDefinition) (Op, string, error) {
+	var op Op
+	var kind string
+	var err error
+
+	switch opDef.Type {
+	case "replace":
+		kind = "Replace"
+		op, err = p.newReplaceOp(opDef)
+
+	case "remove":
+		kind = "Remove"
+		op, err = p.newRemoveOp(opDef)
+
+	case "test":
+		kind = "Test"
+		op, err = p.newTestOp(opDef)
+
+	case "merge":
+		kind = "Merge"
+		op, err = p.newMergeOp(opDef)
+
+	case "strategic-merge":
+		kind = "Strategic merge"
+		op, err = p.newStrategicMergeOp(opDef)
+
+	case "if":
+		kind = "If"
+		op, err = p.newIfOp(opDef)
+
+	case "group":
+		kind = "Group"
+		op, err = p.newGroupOp(opDef)
+
+	case "validate":
+		kind = "Validate"
+		op, err = p.newValidateOp(opDef)
+
+	default:
+		opType, found := registeredOpType(opDef.Type)
+		if !found {
+			return nil, "", fmt.Errorf("Unknown operation type '%s'", opDef.Type)
+		}
+
+		kind = opType.Name
+		op, err = opType.New(opDef)
+	}
+
+	if err != nil {
+		return nil, kind, err
+	}
+
+	if opDef.Error != nil {
+		op = DescriptiveOp{Op: op, ErrorMsg: *opDef.Error}
+	}
+
+	return op, kind, nil
+}
+
 func (parser) newReplaceOp(opDef OpDefinition) (ReplaceOp, error) {
 	if opDef.Path == nil {
 		return ReplaceOp{}, fmt.Errorf("Missing path")
@@ -74,7 +232,13 @@ func (parser) newReplaceOp(opDef OpDefinition) (ReplaceOp, error) {
 		return ReplaceOp{}, fmt.Errorf("Invalid path: %s", err)
 	}
 
-	return ReplaceOp{Path: ptr, Value: *opDef.Value}, nil
+	op := ReplaceOp{Path: ptr, Value: *opDef.Value}
+
+	if opDef.Idempotent != nil {
+		op.Idempotent = *opDef.Idempotent
+	}
+
+	return op, nil
 }
 
 func (parser) newRemoveOp(opDef OpDefinition) (RemoveOp, error) {
@@ -99,16 +263,19 @@ func (parser) newTestOp(opDef OpDefinition) (TestOp, error) {
 		return TestOp{}, fmt.Errorf("Missing path")
 	}
 
-	if opDef.Value == nil && opDef.Absent == nil {
-		return TestOp{}, fmt.Errorf("Missing value or absent")
-	}
-
 	ptr, err := NewPointerFromString(*opDef.Path)
 	if err != nil {
 		return TestOp{}, fmt.Errorf("Invalid path: %s", err)
 	}
 
-	op := TestOp{Path: ptr}
+	op := TestOp{
+		Path:     ptr,
+		Min:      opDef.Min,
+		Max:      opDef.Max,
+		Contains: opDef.Contains,
+		Keys:     opDef.Keys,
+		Length:   opDef.Length,
+	}
 
 	if opDef.Value != nil {
 		op.Value = *opDef.Value
@@ -118,21 +285,222 @@ func (parser) newTestOp(opDef OpDefinition) (TestOp, error) {
 		op.Absent = *opDef.Absent
 	}
 
+	if opDef.Exists != nil {
+		op.Exists = *opDef.Exists
+	}
+
+	if opDef.NormalizeNumbers != nil {
+		op.Equality.NormalizeNumbers = *opDef.NormalizeNumbers
+	}
+
+	if opDef.NullAsAbsent != nil {
+		op.Equality.NullAsAbsent = *opDef.NullAsAbsent
+	}
+
+	if opDef.IgnoreEmpty != nil {
+		op.Equality.IgnoreEmpty = *opDef.IgnoreEmpty
+	}
+
+	if opDef.Is != nil {
+		op.Is = *opDef.Is
+		if !isTestOpType(op.Is) {
+			return TestOp{}, fmt.Errorf("Invalid type '%s': Expected one of '%s'", op.Is, strings.Join(testOpTypes, "', '"))
+		}
+	}
+
+	if opDef.Matches != nil {
+		op.Matches = *opDef.Matches
+		if _, err := regexp.Compile(op.Matches); err != nil {
+			return TestOp{}, fmt.Errorf("Invalid regular expression '%s': %s", op.Matches, err)
+		}
+	}
+
+	if opDef.Value == nil && opDef.Absent == nil && !op.hasAssertions() {
+		return TestOp{}, fmt.Errorf("Missing value, absent or assertions")
+	}
+
+	if op.Absent && (opDef.Value != nil || op.hasAssertions()) {
+		return TestOp{}, fmt.Errorf("Cannot specify value or assertions when testing for absence")
+	}
+
 	return op, nil
 }
 
-func (parser) fmtOpDef(opDef OpDefinition) string {
-	var (
-		redactedVal interface{} = "<redacted>"
-		htmlDecoder             = strings.NewReplacer("\\u003c", "<", "\\u003e", ">")
-	)
+func (parser) newMergeOp(opDef OpDefinition) (MergeOp, error) {
+	if opDef.Path == nil {
+		return MergeOp{}, fmt.Errorf("Missing path")
+	}
+
+	if opDef.Value == nil {
+		return MergeOp{}, fmt.Errorf("Missing value")
+	}
+
+	ptr, err := NewPointerFromString(*opDef.Path)
+	if err != nil {
+		return MergeOp{}, fmt.Errorf("Invalid path: %s", err)
+	}
+
+	op := MergeOp{Path: ptr, Value: *opDef.Value}
+
+	if opDef.Arrays != nil {
+		op.Arrays = MergeArrayStrategy(*opDef.Arrays)
+
+		switch op.Arrays {
+		case MergeArraysReplace, MergeArraysAppend, MergeArraysUnion, MergeArraysByKey:
+		default:
+			return MergeOp{}, fmt.Errorf("Invalid arrays: Expected to find one of the following "+
+				"array strategies: 'replace', 'append', 'union', 'merge' but found '%s'", op.Arrays)
+		}
+	}
+
+	if opDef.Key != nil {
+		if op.Arrays != MergeArraysByKey {
+			return MergeOp{}, fmt.Errorf("Cannot specify key unless arrays is 'merge'")
+		}
+		op.Key = *opDef.Key
+	}
+
+	if opDef.Strict != nil {
+		op.Strict = *opDef.Strict
+	}
+
+	return op, nil
+}
+
+func (p parser) newValidateOp(opDef OpDefinition) (ValidateOp, error) {
+	if opDef.Path == nil {
+		return ValidateOp{}, fmt.Errorf("Missing path")
+	}
+
+	if opDef.Schema == nil {
+		return ValidateOp{}, fmt.Errorf("Missing schema")
+	}
 
 	if opDef.Value != nil {
-		// can't JSON serialize generic interface{} anyway
-		opDef.Value = &redactedVal
+		return ValidateOp{}, fmt.Errorf("Cannot specify value")
+	}
+
+	ptr, err := NewPointerFromString(*opDef.Path)
+	if err != nil {
+		return ValidateOp{}, fmt.Errorf("Invalid path: %s", err)
+	}
+
+	if p.loadSchema == nil {
+		return ValidateOp{}, fmt.Errorf("Expected schema loader to load schema '%s'", *opDef.Schema)
+	}
+
+	schema, err := p.loadSchema(*opDef.Schema)
+	if err != nil {
+		return ValidateOp{}, fmt.Errorf("Invalid schema: %s", err)
+	}
+
+	return ValidateOp{Path: ptr, Schema: schema}, nil
+}
+
+func (parser) newStrategicMergeOp(opDef OpDefinition) (StrategicMergeOp, error) {
+	if opDef.Path == nil {
+		return StrategicMergeOp{}, fmt.Errorf("Missing path")
+	}
+
+	if opDef.Value == nil {
+		return StrategicMergeOp{}, fmt.Errorf("Missing value")
 	}
 
-	bytes, err := json.MarshalIndent(opDef, "", "  ")
+	ptr, err := NewPointerFromString(*opDef.Path)
+	if err != nil {
+		return StrategicMergeOp{}, fmt.Errorf("Invalid path: %s", err)
+	}
+
+	return StrategicMergeOp{Path: ptr, Value: *opDef.Value, MergeKeys: opDef.MergeKeys}, nil
+}
+
+func (p parser) newIfOp(opDef OpDefinition) (IfOp, error) {
+	if opDef.Path != nil {
+		return IfOp{}, fmt.Errorf("Cannot specify path")
+	}
+
+	if opDef.Value != nil {
+		return IfOp{}, fmt.Errorf("Cannot specify value")
+	}
+
+	if opDef.Test == nil {
+		return IfOp{}, fmt.Errorf("Missing test")
+	}
+
+	if opDef.Ops == nil && opDef.Else == nil {
+		return IfOp{}, fmt.Errorf("Missing ops or else")
+	}
+
+	if len(opDef.Test.Type) > 0 && opDef.Test.Type != "test" {
+		return IfOp{}, fmt.Errorf("Invalid test: Expected type to be 'test' but found '%s'", opDef.Test.Type)
+	}
+
+	testOp, err := p.newTestOp(*opDef.Test)
+	if err != nil {
+		return IfOp{}, fmt.Errorf("Invalid test: %s", err)
+	}
+
+	op := IfOp{Test: testOp}
+
+	if opDef.Ops != nil {
+		op.Then, err = p.newOps(opDef.Ops)
+		if err != nil {
+			return IfOp{}, fmt.Errorf("Invalid ops: %s", err)
+		}
+	}
+
+	if opDef.Else != nil {
+		op.Else, err = p.newOps(opDef.Else)
+		if err != nil {
+			return IfOp{}, fmt.Errorf("Invalid else: %s", err)
+		}
+	}
+
+	return op, nil
+}
+
+func (p parser) newGroupOp(opDef OpDefinition) (GroupOp, error) {
+	if opDef.Path == nil {
+		return GroupOp{}, fmt.Errorf("Missing path")
+	}
+
+	if opDef.Value != nil {
+		return GroupOp{}, fmt.Errorf("Cannot specify value")
+	}
+
+	if opDef.Ops == nil {
+		return GroupOp{}, fmt.Errorf("Missing ops")
+	}
+
+	ptr, err := NewPointerFromString(*opDef.Path)
+	if err != nil {
+		return GroupOp{}, fmt.Errorf("Invalid path: %s", err)
+	}
+
+	for _, token := range ptr.Tokens() {
+		if _, ok := token.(AfterLastIndexToken); ok {
+			return GroupOp{}, fmt.Errorf("Invalid path: Expected not to find after last index token")
+		}
+	}
+
+	ops, err := p.newOps(opDef.Ops)
+	if err != nil {
+		return GroupOp{}, fmt.Errorf("Invalid ops: %s", err)
+	}
+
+	// custom operations may not support relative paths
+	_, err = rebaseOps(ops, ptr)
+	if err != nil {
+		return GroupOp{}, fmt.Errorf("Invalid ops: %s", err)
+	}
+
+	return GroupOp{Path: ptr, Ops: ops}, nil
+}
+
+func (p parser) fmtOpDef(opDef OpDefinition) string {
+	htmlDecoder := strings.NewReplacer("\\u003c", "<", "\\u003e", ">")
+
+	bytes, err := json.MarshalIndent(p.redactOpDef(opDef), "", "  ")
 	if err != nil {
 		return "<unknown>"
 	}
@@ -140,6 +508,45 @@ func (parser) fmtOpDef(opDef OpDefinition) string {
 	return htmlDecoder.Replace(string(bytes))
 }
 
+func (p parser) redactOpDef(opDef OpDefinition) OpDefinition {
+	var redactedVal interface{} = "<redacted>"
+
+	if opDef.Value != nil {
+		// can't JSON serialize generic interface{} anyway
+		opDef.Value = &redactedVal
+	}
+
+	if opDef.Extra != nil {
+		extra := map[string]interface{}{}
+		for key := range opDef.Extra {
+			extra[key] = redactedVal
+		}
+		opDef.Extra = extra
+	}
+
+	if opDef.Test != nil {
+		test := p.redactOpDef(*opDef.Test)
+		opDef.Test = &test
+	}
+
+	opDef.Ops = p.redactOpDefs(opDef.Ops)
+	opDef.Else = p.redactOpDefs(opDef.Else)
+
+	return opDef
+}
+
+func (p parser) redactOpDefs(opDefs []OpDefinition) []OpDefinition {
+	if opDefs == nil {
+		return nil
+	}
+
+	result := []OpDefinition{}
+	for _, opDef := range opDefs {
+		result = append(result, p.redactOpDef(opDef))
+	}
+	return result
+}
+
 func NewOpDefinitionsFromOps(ops Ops) ([]OpDefinition, error) {
 	opDefs := []OpDefinition{}
 
@@ -149,11 +556,17 @@ func NewOpDefinitionsFromOps(ops Ops) ([]OpDefinition, error) {
 			path := typedOp.Path.String()
 			val := typedOp.Value
 
-			opDefs = append(opDefs, OpDefinition{
+			opDef := OpDefinition{
 				Type:  "replace",
 				Path:  &path,
 				Value: &val,
-			})
+			}
+
+			if typedOp.Idempotent {
+				opDef.Idempotent = &typedOp.Idempotent
+			}
+
+			opDefs = append(opDefs, opDef)
 
 		case RemoveOp:
 			path := typedOp.Path.String()
@@ -164,26 +577,156 @@ func NewOpDefinitionsFromOps(ops Ops) ([]OpDefinition, error) {
 			})
 
 		case TestOp:
+			opDefs = append(opDefs, newTestOpDefinition(typedOp))
+
+		case MergeOp:
 			path := typedOp.Path.String()
 			val := typedOp.Value
 
 			opDef := OpDefinition{
-				Type: "test",
-				Path: &path,
+				Type:  "merge",
+				Path:  &path,
+				Value: &val,
+			}
+
+			if len(typedOp.Arrays) > 0 {
+				arrays := string(typedOp.Arrays)
+				opDef.Arrays = &arrays
+			}
+
+			if len(typedOp.Key) > 0 {
+				opDef.Key = &typedOp.Key
+			}
+
+			if typedOp.Strict {
+				opDef.Strict = &typedOp.Strict
+			}
+
+			opDefs = append(opDefs, opDef)
+
+		case StrategicMergeOp:
+			path := typedOp.Path.String()
+			val := typedOp.Value
+
+			opDefs = append(opDefs, OpDefinition{
+				Type:      "strategic-merge",
+				Path:      &path,
+				Value:     &val,
+				MergeKeys: typedOp.MergeKeys,
+			})
+
+		case IfOp:
+			test := newTestOpDefinition(typedOp.Test)
+
+			opDef := OpDefinition{Type: "if", Test: &test}
+
+			if typedOp.Then != nil {
+				thenDefs, err := NewOpDefinitionsFromOps(typedOp.Then)
+				if err != nil {
+					return nil, fmt.Errorf("If operation [%d]: %s", i, err)
+				}
+				opDef.Ops = thenDefs
 			}
 
-			if typedOp.Absent {
-				opDef.Absent = &typedOp.Absent
-			} else {
-				opDef.Value = &val
+			if typedOp.Else != nil {
+				elseDefs, err := NewOpDefinitionsFromOps(typedOp.Else)
+				if err != nil {
+					return nil, fmt.Errorf("If operation [%d]: %s", i, err)
+				}
+				opDef.Else = elseDefs
 			}
 
 			opDefs = append(opDefs, opDef)
 
+		case GroupOp:
+			path := typedOp.Path.String()
+
+			childDefs, err := NewOpDefinitionsFromOps(typedOp.Ops)
+			if err != nil {
+				return nil, fmt.Errorf("Group operation [%d]: %s", i, err)
+			}
+
+			opDefs = append(opDefs, OpDefinition{
+				Type: "group",
+				Path: &path,
+				Ops:  childDefs,
+			})
+
+		case ValidateOp:
+			if len(typedOp.Schema.Source) == 0 {
+				return nil, fmt.Errorf("Validate operation [%d]: Expected schema to be loaded from a file", i)
+			}
+
+			path := typedOp.Path.String()
+
+			opDefs = append(opDefs, OpDefinition{
+				Type:   "validate",
+				Path:   &path,
+				Schema: &typedOp.Schema.Source,
+			})
+
 		default:
-			return nil, fmt.Errorf("Unknown operation [%d] with type '%t'", i, op)
+			opType, found := registeredOpTypeOf(op)
+			if !found {
+				return nil, fmt.Errorf("Unknown operation [%d] with type '%t'", i, op)
+			}
+
+			opDef, err := opType.definition(op)
+			if err != nil {
+				return nil, fmt.Errorf("%s operation [%d]: %s", opType.Name, i, err)
+			}
+
+			opDefs = append(opDefs, opDef)
 		}
 	}
 
 	return opDefs, nil
 }
+
+func newTestOpDefinition(op TestOp) OpDefinition {
+	path := op.Path.String()
+	val := op.Value
+
+	opDef := OpDefinition{
+		Type: "test",
+		Path: &path,
+	}
+
+	if op.Absent {
+		opDef.Absent = &op.Absent
+	} else if op.comparesValue() {
+		opDef.Value = &val
+	}
+
+	if op.Exists {
+		opDef.Exists = &op.Exists
+	}
+
+	if len(op.Is) > 0 {
+		opDef.Is = &op.Is
+	}
+
+	if len(op.Matches) > 0 {
+		opDef.Matches = &op.Matches
+	}
+
+	opDef.Min = op.Min
+	opDef.Max = op.Max
+	opDef.Contains = op.Contains
+	opDef.Keys = op.Keys
+	opDef.Length = op.Length
+
+	if op.Equality.NormalizeNumbers {
+		opDef.NormalizeNumbers = &op.Equality.NormalizeNumbers
+	}
+
+	if op.Equality.NullAsAbsent {
+		opDef.NullAsAbsent = &op.Equality.NullAsAbsent
+	}
+
+	if op.Equality.IgnoreEmpty {
+		opDef.IgnoreEmpty = &op.Equality.IgnoreEmpty
+	}
+
+	return opDef
+}
@@ -2,6 +2,9 @@ package patch_test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -80,6 +83,15 @@ var _ = Describe("NewOpsFromDefinitions", func() {
 			})))
 		})
 
+		It("supports idempotent flag", func() {
+			ops, err := NewOpsFromDefinitions([]OpDefinition{{Type: "replace", Path: &path, Value: &val, Idempotent: &trueBool}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				ReplaceOp{Path: MustNewPointerFromString("/abc"), Value: 123, Idempotent: true},
+			})))
+		})
+
 		It("requires path", func() {
 			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "replace"}})
 			Expect(err).To(HaveOccurred())
@@ -184,13 +196,62 @@ var _ = Describe("NewOpsFromDefinitions", func() {
 		It("requires value or absent flag", func() {
 			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "test", Path: &path}})
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal(`Test operation [0]: Missing value or absent within
+			Expect(err.Error()).To(Equal(`Test operation [0]: Missing value, absent or assertions within
 {
   "Type": "test",
   "Path": "/abc"
 }`))
 		})
 
+		It("supports assertions", func() {
+			var (
+				exists   = true
+				is       = "string"
+				matches  = "^a"
+				min      = 1.0
+				max      = 2.5
+				length   = 3
+				contains = []interface{}{"a"}
+				keys     = []string{"a"}
+			)
+
+			ops, err := NewOpsFromDefinitions([]OpDefinition{
+				{Type: "test", Path: &path, Exists: &exists, Is: &is, Matches: &matches, Length: &length},
+				{Type: "test", Path: &path, Value: &val, Min: &min, Max: &max},
+				{Type: "test", Path: &path, Contains: contains, Keys: keys},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				TestOp{Path: MustNewPointerFromString("/abc"), Exists: true, Is: "string", Matches: "^a", Length: &length},
+				TestOp{Path: MustNewPointerFromString("/abc"), Value: 123, Min: &min, Max: &max},
+				TestOp{Path: MustNewPointerFromString("/abc"), Contains: contains, Keys: keys},
+			})))
+		})
+
+		It("requires known type and valid regular expression", func() {
+			var (
+				is      = "map"
+				matches = "("
+			)
+
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "test", Path: &path, Is: &is}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Test operation [0]: Invalid type 'map': Expected one of 'null', 'boolean', 'string', 'number', 'integer', 'object', 'array' within"))
+
+			_, err = NewOpsFromDefinitions([]OpDefinition{{Type: "test", Path: &path, Matches: &matches}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Test operation [0]: Invalid regular expression '(': "))
+		})
+
+		It("does not allow assertions when testing for absence", func() {
+			length := 1
+
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "test", Path: &path, Absent: &trueBool, Length: &length}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Test operation [0]: Cannot specify value or assertions when testing for absence within"))
+		})
+
 		It("requires valid path", func() {
 			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "test", Path: &invalidPath, Value: &val}})
 			Expect(err).To(HaveOccurred())
@@ -202,6 +263,354 @@ var _ = Describe("NewOpsFromDefinitions", func() {
 }`))
 		})
 	})
+
+	Describe("merge", func() {
+		It("supports path and value", func() {
+			ops, err := NewOpsFromDefinitions([]OpDefinition{{Type: "merge", Path: &path, Value: &complexVal, Error: &errorMsg}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				DescriptiveOp{
+					Op:       MergeOp{Path: MustNewPointerFromString("/abc"), Value: complexVal},
+					ErrorMsg: errorMsg,
+				},
+			})))
+		})
+
+		It("supports array strategies and strict mode", func() {
+			var (
+				mergeArrays  = "merge"
+				appendArrays = "append"
+				key          = "id"
+			)
+
+			ops, err := NewOpsFromDefinitions([]OpDefinition{
+				{Type: "merge", Path: &path, Value: &val, Arrays: &mergeArrays, Key: &key, Strict: &trueBool},
+				{Type: "merge", Path: &path, Value: &val, Arrays: &appendArrays},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				MergeOp{Path: MustNewPointerFromString("/abc"), Value: 123, Arrays: MergeArraysByKey, Key: "id", Strict: true},
+				MergeOp{Path: MustNewPointerFromString("/abc"), Value: 123, Arrays: MergeArraysAppend},
+			})))
+		})
+
+		It("requires valid array strategy", func() {
+			unknown := "unknown"
+
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "merge", Path: &path, Value: &val, Arrays: &unknown}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Merge operation [0]: Invalid arrays: Expected to find one of the following array strategies: 'replace', 'append', 'union', 'merge' but found 'unknown' within"))
+		})
+
+		It("does not allow key unless arrays are merged", func() {
+			key := "id"
+
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "merge", Path: &path, Value: &val, Key: &key}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Merge operation [0]: Cannot specify key unless arrays is 'merge' within"))
+		})
+
+		It("requires path", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "merge", Value: &val}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`Merge operation [0]: Missing path within
+{
+  "Type": "merge",
+  "Value": "<redacted>"
+}`))
+		})
+
+		It("requires value", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "merge", Path: &path}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Merge operation [0]: Missing value within"))
+		})
+
+		It("requires valid path", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "merge", Path: &invalidPath, Value: &val}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Merge operation [0]: Invalid path: Expected to start with '/' within"))
+		})
+	})
+
+	Describe("strategic-merge", func() {
+		It("supports path, value and merge keys", func() {
+			ops, err := NewOpsFromDefinitions([]OpDefinition{
+				{Type: "strategic-merge", Path: &path, Value: &complexVal, MergeKeys: map[string]string{"/jobs": "id"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				StrategicMergeOp{Path: MustNewPointerFromString("/abc"), Value: complexVal, MergeKeys: map[string]string{"/jobs": "id"}},
+			})))
+		})
+
+		It("requires path and value", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "strategic-merge", Value: &val}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Strategic merge operation [0]: Missing path within"))
+
+			_, err = NewOpsFromDefinitions([]OpDefinition{{Type: "strategic-merge", Path: &path}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Strategic merge operation [0]: Missing value within"))
+		})
+	})
+
+	Describe("if", func() {
+		var (
+			testDef = OpDefinition{Path: &path, Value: &val}
+			opsDefs = []OpDefinition{{Type: "remove", Path: &path}}
+		)
+
+		It("supports test with ops and else", func() {
+			ops, err := NewOpsFromDefinitions([]OpDefinition{
+				{Type: "if", Test: &testDef, Ops: opsDefs, Else: opsDefs, Error: &errorMsg},
+				{Type: "if", Test: &OpDefinition{Type: "test", Path: &path, Absent: &trueBool}, Ops: opsDefs},
+				{Type: "if", Test: &testDef, Else: []OpDefinition{}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				DescriptiveOp{
+					Op: IfOp{
+						Test: TestOp{Path: MustNewPointerFromString("/abc"), Value: 123},
+						Then: Ops{RemoveOp{Path: MustNewPointerFromString("/abc")}},
+						Else: Ops{RemoveOp{Path: MustNewPointerFromString("/abc")}},
+					},
+					ErrorMsg: errorMsg,
+				},
+				IfOp{
+					Test: TestOp{Path: MustNewPointerFromString("/abc"), Absent: true},
+					Then: Ops{RemoveOp{Path: MustNewPointerFromString("/abc")}},
+				},
+				IfOp{
+					Test: TestOp{Path: MustNewPointerFromString("/abc"), Value: 123},
+					Else: Ops(nil),
+				},
+			})))
+		})
+
+		It("requires test", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "if", Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`If operation [0]: Missing test within
+{
+  "Type": "if",
+  "Ops": [
+    {
+      "Type": "remove",
+      "Path": "/abc"
+    }
+  ]
+}`))
+		})
+
+		It("requires ops or else", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "if", Test: &testDef}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`If operation [0]: Missing ops or else within
+{
+  "Type": "if",
+  "Test": {
+    "Path": "/abc",
+    "Value": "<redacted>"
+  }
+}`))
+		})
+
+		It("does not allow path or value", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "if", Path: &path, Test: &testDef, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("If operation [0]: Cannot specify path within"))
+
+			_, err = NewOpsFromDefinitions([]OpDefinition{{Type: "if", Value: &val, Test: &testDef, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("If operation [0]: Cannot specify value within"))
+		})
+
+		It("requires valid test", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "if", Test: &OpDefinition{Path: &path}, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("If operation [0]: Invalid test: Missing value, absent or assertions within"))
+
+			_, err = NewOpsFromDefinitions([]OpDefinition{{Type: "if", Test: &OpDefinition{Type: "remove", Path: &path}, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("If operation [0]: Invalid test: Expected type to be 'test' but found 'remove' within"))
+		})
+
+		It("requires valid nested operations", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "if", Test: &testDef, Ops: []OpDefinition{{Type: "remove"}}}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("If operation [0]: Invalid ops: Remove operation [0]: Missing path within"))
+
+			_, err = NewOpsFromDefinitions([]OpDefinition{{Type: "if", Test: &testDef, Else: []OpDefinition{{Type: "op"}}}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("If operation [0]: Invalid else: Unknown operation [0] with type 'op' within"))
+		})
+	})
+
+	Describe("group", func() {
+		opsDefs := []OpDefinition{{Type: "remove", Path: &path}}
+
+		It("supports path with ops", func() {
+			ops, err := NewOpsFromDefinitions([]OpDefinition{
+				{Type: "group", Path: &path, Ops: opsDefs, Error: &errorMsg},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				DescriptiveOp{
+					Op: GroupOp{
+						Path: MustNewPointerFromString("/abc"),
+						Ops:  Ops{RemoveOp{Path: MustNewPointerFromString("/abc")}},
+					},
+					ErrorMsg: errorMsg,
+				},
+			})))
+		})
+
+		It("requires path", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "group", Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Group operation [0]: Missing path within"))
+		})
+
+		It("requires ops", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "group", Path: &path}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`Group operation [0]: Missing ops within
+{
+  "Type": "group",
+  "Path": "/abc"
+}`))
+		})
+
+		It("does not allow value", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "group", Path: &path, Value: &val, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Group operation [0]: Cannot specify value within"))
+		})
+
+		It("requires valid path", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "group", Path: &invalidPath, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Group operation [0]: Invalid path: Expected to start with '/' within"))
+
+			afterLastPath := "/abc/-"
+
+			_, err = NewOpsFromDefinitions([]OpDefinition{{Type: "group", Path: &afterLastPath, Ops: opsDefs}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Group operation [0]: Invalid path: Expected not to find after last index token within"))
+		})
+
+		It("requires valid nested operations", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "group", Path: &path, Ops: []OpDefinition{{Type: "remove"}}}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Group operation [0]: Invalid ops: Remove operation [0]: Missing path within"))
+		})
+	})
+
+	Describe("validate", func() {
+		var (
+			dir        string
+			schemaFile = "schema.yml"
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			dir, err = ioutil.TempDir("", "go-patch-schema")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(dir, schemaFile), []byte("type: object\nrequired: [name]\n"), 0600)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("supports path and schema file relative to loader directory", func() {
+			ops, err := NewOpsFromDefinitionsWithSchemaLoader([]OpDefinition{
+				{Type: "validate", Path: &path, Schema: &schemaFile, Error: &errorMsg},
+			}, NewSchemaDirLoader(dir))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ops).To(Equal(Ops([]Op{
+				DescriptiveOp{
+					Op: ValidateOp{
+						Path: MustNewPointerFromString("/abc"),
+						Schema: Schema{
+							Definition: map[interface{}]interface{}{"type": "object", "required": []interface{}{"name"}},
+							Source:     schemaFile,
+						},
+					},
+					ErrorMsg: errorMsg,
+				},
+			})))
+		})
+
+		It("loads schemas of nested operations", func() {
+			ops, err := NewOpsFromDefinitionsWithSchemaLoader([]OpDefinition{
+				{Type: "group", Path: &path, Ops: []OpDefinition{{Type: "validate", Path: &path, Schema: &schemaFile}}},
+			}, NewSchemaDirLoader(dir))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ops[0].(GroupOp).Ops[0].(ValidateOp).Schema.Source).To(Equal(schemaFile))
+		})
+
+		It("requires schema loader to read schema files", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "validate", Path: &path, Schema: &schemaFile}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Expected schema loader to load schema 'schema.yml' within"))
+		})
+
+		It("requires schema files within loader directory", func() {
+			absPath := filepath.Join(dir, schemaFile)
+			outsidePath := "../schema.yml"
+
+			_, err := NewOpsFromDefinitionsWithSchemaLoader([]OpDefinition{
+				{Type: "validate", Path: &path, Schema: &absPath},
+			}, NewSchemaDirLoader(dir))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Invalid schema: Expected schema path '" + absPath + "' to be relative within"))
+
+			_, err = NewOpsFromDefinitionsWithSchemaLoader([]OpDefinition{
+				{Type: "validate", Path: &path, Schema: &outsidePath},
+			}, NewSchemaDirLoader(dir))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Invalid schema: Expected schema path '../schema.yml' to be within '" + dir + "'"))
+		})
+
+		It("requires path", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "validate", Schema: &schemaFile}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Missing path within"))
+		})
+
+		It("requires schema", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "validate", Path: &path}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Missing schema within"))
+		})
+
+		It("does not allow value", func() {
+			_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "validate", Path: &path, Schema: &schemaFile, Value: &val}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Cannot specify value within"))
+		})
+
+		It("requires readable schema file", func() {
+			missingFile := "missing.yml"
+
+			_, err := NewOpsFromDefinitionsWithSchemaLoader([]OpDefinition{
+				{Type: "validate", Path: &path, Schema: &missingFile},
+			}, NewSchemaDirLoader(dir))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validate operation [0]: Invalid schema: Reading schema '" + filepath.Join(dir, missingFile) + "'"))
+		})
+	})
 })
 
 var _ = Describe("NewOpDefinitionsFromOps", func() {
@@ -256,4 +665,259 @@ var _ = Describe("NewOpDefinitionsFromOps", func() {
     }
 ]`))
 	})
+
+	It("supports test assertions serialized", func() {
+		var (
+			min    = 2.0
+			length = 3
+		)
+
+		ops := Ops([]Op{
+			TestOp{Path: MustNewPointerFromString("/abc"), Exists: true, Is: "array", Length: &length, Contains: []interface{}{"a"}},
+			TestOp{Path: MustNewPointerFromString("/abc"), Matches: "^a", Keys: []string{"a"}, Min: &min},
+			TestOp{Path: MustNewPointerFromString("/abc"), Value: 123, Min: &min},
+		})
+
+		opDefs, err := NewOpDefinitionsFromOps(ops)
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: test
+  path: /abc
+  exists: true
+  is: array
+  contains:
+  - a
+  length: 3
+- type: test
+  path: /abc
+  matches: ^a
+  min: 2
+  keys:
+  - a
+- type: test
+  path: /abc
+  value: 123
+  min: 2
+`))
+
+		var parsedDefs []OpDefinition
+		Expect(yaml.Unmarshal(bs, &parsedDefs)).To(Succeed())
+
+		parsedOps, err := NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedOps).To(Equal(ops))
+	})
+
+	It("supports equality of test operations serialized", func() {
+		ops := Ops([]Op{
+			TestOp{Path: MustNewPointerFromString("/abc"), Value: 1, Equality: Equality{NormalizeNumbers: true}},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/abc"), Absent: true, Equality: Equality{NullAsAbsent: true, IgnoreEmpty: true}},
+				Then: Ops{RemoveOp{Path: MustNewPointerFromString("/abc")}},
+			},
+		})
+
+		opDefs, err := NewOpDefinitionsFromOps(ops)
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: test
+  path: /abc
+  value: 1
+  normalizenumbers: true
+- type: if
+  test:
+    type: test
+    path: /abc
+    absent: true
+    nullasabsent: true
+    ignoreempty: true
+  ops:
+  - type: remove
+    path: /abc
+`))
+
+		var parsedDefs []OpDefinition
+		Expect(yaml.Unmarshal(bs, &parsedDefs)).To(Succeed())
+
+		parsedOps, err := NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedOps).To(Equal(ops))
+
+		_, err = parsedOps.Apply(map[interface{}]interface{}{"abc": 1.0})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("supports 'if' operations serialized", func() {
+		ops := Ops([]Op{
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/abc"), Absent: true},
+				Then: Ops{RemoveOp{Path: MustNewPointerFromString("/abc")}},
+				Else: Ops{ReplaceOp{Path: MustNewPointerFromString("/abc"), Value: 123}},
+			},
+		})
+
+		opDefs, err := NewOpDefinitionsFromOps(ops)
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: if
+  test:
+    type: test
+    path: /abc
+    absent: true
+  ops:
+  - type: remove
+    path: /abc
+  else:
+  - type: replace
+    path: /abc
+    value: 123
+`))
+
+		var parsedDefs []OpDefinition
+
+		err = yaml.Unmarshal(bs, &parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		parsedOps, err := NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedOps).To(Equal(ops))
+	})
+
+	It("supports 'group' operations serialized", func() {
+		ops := Ops([]Op{
+			GroupOp{
+				Path: MustNewPointerFromString("/abc"),
+				Ops:  Ops{ReplaceOp{Path: MustNewPointerFromString("/def"), Value: 123}},
+			},
+		})
+
+		opDefs, err := NewOpDefinitionsFromOps(ops)
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: group
+  path: /abc
+  ops:
+  - type: replace
+    path: /def
+    value: 123
+`))
+
+		var parsedDefs []OpDefinition
+
+		err = yaml.Unmarshal(bs, &parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		parsedOps, err := NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedOps).To(Equal(ops))
+	})
+
+	It("supports 'strategic-merge' operations serialized", func() {
+		ops := Ops{
+			StrategicMergeOp{
+				Path:      MustNewPointerFromString("/abc"),
+				Value:     map[interface{}]interface{}{"a": 1},
+				MergeKeys: map[string]string{"/jobs": "id"},
+			},
+		}
+
+		opDefs, err := NewOpDefinitionsFromOps(ops)
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: strategic-merge
+  path: /abc
+  value:
+    a: 1
+  mergekeys:
+    /jobs: id
+`))
+
+		var parsedDefs []OpDefinition
+		Expect(yaml.Unmarshal(bs, &parsedDefs)).To(Succeed())
+
+		parsedOps, err := NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedOps).To(Equal(ops))
+	})
+
+	It("supports 'merge' operations serialized", func() {
+		opDefs, err := NewOpDefinitionsFromOps(Ops{
+			MergeOp{Path: MustNewPointerFromString("/abc"), Value: map[interface{}]interface{}{"a": nil}},
+			MergeOp{Path: MustNewPointerFromString("/abc"), Value: 1, Arrays: MergeArraysByKey, Key: "id", Strict: true},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: merge
+  path: /abc
+  value:
+    a: null
+- type: merge
+  path: /abc
+  value: 1
+  arrays: merge
+  key: id
+  strict: true
+`))
+	})
+
+	It("supports idempotent 'replace' operations serialized", func() {
+		opDefs, err := NewOpDefinitionsFromOps(Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/abc/-"), Value: 1, Idempotent: true},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: replace
+  path: /abc/-
+  value: 1
+  idempotent: true
+`))
+	})
+
+	It("supports 'validate' operations serialized", func() {
+		opDefs, err := NewOpDefinitionsFromOps(Ops{
+			ValidateOp{Path: MustNewPointerFromString("/abc"), Schema: Schema{Source: "schema.yml"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: validate
+  path: /abc
+  schema: schema.yml
+`))
+
+		_, err = NewOpDefinitionsFromOps(Ops{ValidateOp{Path: MustNewPointerFromString("/abc")}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Validate operation [0]: Expected schema to be loaded from a file"))
+	})
 })
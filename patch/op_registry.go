@@ -0,0 +1,131 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// OpType describes custom operation that can be used in definitions along with built-in operations
+type OpType struct {
+	Name string // value of 'type' field (ex: 'generate-certificate')
+	Op   Op     // example of the operation to recognize it during serialization (ex: GenerateCertificateOp{})
+
+	// New creates operation; fields that are not known to OpDefinition are available via Extra
+	New func(OpDefinition) (Op, error)
+
+	// Definition serializes operation; type field is set automatically
+	Definition func(Op) (OpDefinition, error)
+
+	// Rebase returns operation with paths relative to the base (ex: '/instance_groups/name=api');
+	// operations without it cannot be used within group operations
+	Rebase func(op Op, base Pointer) (Op, error)
+}
+
+var builtinOpTypes = []string{"replace", "remove", "test", "merge", "strategic-merge", "if", "group", "validate"}
+
+var opRegistry = struct {
+	sync.RWMutex
+	byName map[string]OpType
+	byType map[reflect.Type]OpType
+}{
+	byName: map[string]OpType{},
+	byType: map[reflect.Type]OpType{},
+}
+
+// RegisterOpType makes custom operation available to NewOpsFromDefinitions and NewOpDefinitionsFromOps
+func RegisterOpType(opType OpType) error {
+	if len(opType.Name) == 0 {
+		return fmt.Errorf("Expected operation type to have a name")
+	}
+
+	if opType.New == nil {
+		return fmt.Errorf("Expected operation type '%s' to have a constructor", opType.Name)
+	}
+
+	if opType.Op == nil || opType.Definition == nil {
+		return fmt.Errorf("Expected operation type '%s' to have an example operation and a serializer", opType.Name)
+	}
+
+	for _, name := range builtinOpTypes {
+		if name == opType.Name {
+			return fmt.Errorf("Expected operation type '%s' to not be a built-in operation type", opType.Name)
+		}
+	}
+
+	opRegistry.Lock()
+	defer opRegistry.Unlock()
+
+	if _, found := opRegistry.byName[opType.Name]; found {
+		return fmt.Errorf("Expected operation type '%s' to not be already registered", opType.Name)
+	}
+
+	if existing, found := opRegistry.byType[reflect.TypeOf(opType.Op)]; found {
+		errMsg := "Expected operation '%T' to not be already registered as operation type '%s'"
+		return fmt.Errorf(errMsg, opType.Op, existing.Name)
+	}
+
+	opRegistry.byName[opType.Name] = opType
+	opRegistry.byType[reflect.TypeOf(opType.Op)] = opType
+
+	return nil
+}
+
+// UnregisterOpType removes custom operation type (ex: in tests)
+func UnregisterOpType(name string) {
+	opRegistry.Lock()
+	defer opRegistry.Unlock()
+
+	if opType, found := opRegistry.byName[name]; found {
+		delete(opRegistry.byName, name)
+		delete(opRegistry.byType, reflect.TypeOf(opType.Op))
+	}
+}
+
+func registeredOpType(name string) (OpType, bool) {
+	opRegistry.RLock()
+	defer opRegistry.RUnlock()
+
+	opType, found := opRegistry.byName[name]
+	return opType, found
+}
+
+func registeredOpTypeOf(op Op) (OpType, bool) {
+	opRegistry.RLock()
+	defer opRegistry.RUnlock()
+
+	opType, found := opRegistry.byType[reflect.TypeOf(op)]
+	return opType, found
+}
+
+func (t OpType) definition(op Op) (OpDefinition, error) {
+	opDef, err := t.Definition(op)
+	if err != nil {
+		return OpDefinition{}, err
+	}
+
+	opDef.Type = t.Name
+
+	// extra fields would be lost or conflict with known fields during marshaling
+	for key := range opDef.Extra {
+		if isOpDefinitionField(key) {
+			return OpDefinition{}, fmt.Errorf("Expected extra field '%s' to not conflict with definition fields", key)
+		}
+	}
+
+	return opDef, nil
+}
+
+// isOpDefinitionField returns true if name refers to one of OpDefinition fields (case insensitive as in JSON)
+func isOpDefinitionField(name string) bool {
+	defType := reflect.TypeOf(OpDefinition{})
+
+	for i := 0; i < defType.NumField(); i++ {
+		if field := defType.Field(i); field.Name != "Extra" && strings.EqualFold(field.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,258 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+type certOp struct {
+	Path       Pointer
+	CommonName string
+}
+
+func (op certOp) Apply(doc interface{}) (interface{}, error) {
+	return ReplaceOp{Path: op.Path, Value: "cert for " + op.CommonName}.Apply(doc)
+}
+
+var certOpType = OpType{
+	Name: "generate-certificate",
+	Op:   certOp{},
+
+	New: func(opDef OpDefinition) (Op, error) {
+		if opDef.Path == nil {
+			return nil, fmt.Errorf("Missing path")
+		}
+
+		commonName, ok := opDef.Extra["common_name"].(string)
+		if !ok {
+			return nil, fmt.Errorf("Missing common_name")
+		}
+
+		ptr, err := NewPointerFromString(*opDef.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return certOp{Path: ptr, CommonName: commonName}, nil
+	},
+
+	Definition: func(op Op) (OpDefinition, error) {
+		path := op.(certOp).Path.String()
+		return OpDefinition{Path: &path, Extra: map[string]interface{}{"common_name": op.(certOp).CommonName}}, nil
+	},
+
+	Rebase: func(op Op, base Pointer) (Op, error) {
+		typedOp := op.(certOp)
+		typedOp.Path = base.Concat(typedOp.Path)
+		return typedOp, nil
+	},
+}
+
+var _ = Describe("RegisterOpType", func() {
+	BeforeEach(func() {
+		Expect(RegisterOpType(certOpType)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		UnregisterOpType(certOpType.Name)
+	})
+
+	It("creates custom operations from definitions with extra fields", func() {
+		var opDefs []OpDefinition
+
+		err := yaml.Unmarshal([]byte(`
+- type: generate-certificate
+  path: /cert?
+  common_name: example.com
+  error: generating cert
+`), &opDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opDefs[0].Extra).To(Equal(map[string]interface{}{"common_name": "example.com"}))
+
+		ops, err := NewOpsFromDefinitions(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(Equal(Ops{DescriptiveOp{
+			Op:       certOp{Path: MustNewPointerFromString("/cert?"), CommonName: "example.com"},
+			ErrorMsg: "generating cert",
+		}}))
+
+		res, err := ops.Apply(map[interface{}]interface{}{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"cert": "cert for example.com"}))
+	})
+
+	It("applies custom operations within groups", func() {
+		var opDefs []OpDefinition
+
+		err := yaml.Unmarshal([]byte(`
+- type: group
+  path: /certs
+  ops:
+  - type: generate-certificate
+    path: /ca?
+    common_name: ca
+`), &opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		ops, err := NewOpsFromDefinitions(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		res, err := ops.Apply(map[interface{}]interface{}{"certs": map[interface{}]interface{}{}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{
+			"certs": map[interface{}]interface{}{"ca": "cert for ca"},
+		}))
+	})
+
+	It("returns an error if custom operation without rebase is used within groups", func() {
+		UnregisterOpType(certOpType.Name)
+
+		absoluteType := certOpType
+		absoluteType.Rebase = nil
+		Expect(RegisterOpType(absoluteType)).To(Succeed())
+
+		path := "/certs"
+		childPath := "/ca"
+
+		_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "group", Path: &path, Ops: []OpDefinition{
+			{Type: "generate-certificate", Path: &childPath, Extra: map[string]interface{}{"common_name": "ca"}},
+		}}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Group operation [0]: Invalid ops: " +
+			"Expected to find operation with a relative path but found 'patch_test.certOp' within"))
+	})
+
+	It("returns an error if custom operation cannot be created", func() {
+		path := "/cert"
+
+		_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "generate-certificate", Path: &path}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`generate-certificate operation [0]: Missing common_name within
+{
+  "Type": "generate-certificate",
+  "Path": "/cert"
+}`))
+	})
+
+	It("serializes custom operations into definitions", func() {
+		ops := Ops{
+			certOp{Path: MustNewPointerFromString("/cert"), CommonName: "example.com"},
+			GroupOp{Path: MustNewPointerFromString("/certs"), Ops: Ops{
+				certOp{Path: MustNewPointerFromString("/ca"), CommonName: "ca"},
+			}},
+		}
+
+		opDefs, err := NewOpDefinitionsFromOps(ops)
+		Expect(err).ToNot(HaveOccurred())
+
+		bytes, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes)).To(Equal(`- type: generate-certificate
+  path: /cert
+  common_name: example.com
+- type: group
+  path: /certs
+  ops:
+  - type: generate-certificate
+    path: /ca
+    common_name: ca
+`))
+
+		var parsedDefs []OpDefinition
+		Expect(yaml.Unmarshal(bytes, &parsedDefs)).To(Succeed())
+
+		parsedOps, err := NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedOps).To(Equal(ops))
+	})
+
+	It("marshals extra fields into JSON", func() {
+		opDefs, err := NewOpDefinitionsFromOps(Ops{certOp{Path: MustNewPointerFromString("/cert"), CommonName: "example.com"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		bytes, err := json.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes)).To(Equal(`[{"Type":"generate-certificate","Path":"/cert","common_name":"example.com"}]`))
+
+		var parsedDefs []OpDefinition
+		Expect(json.Unmarshal(bytes, &parsedDefs)).To(Succeed())
+		Expect(parsedDefs).To(Equal(opDefs))
+	})
+
+	It("returns an error if extra fields conflict with definition fields", func() {
+		UnregisterOpType(certOpType.Name)
+
+		conflictingType := certOpType
+		conflictingType.Definition = func(op Op) (OpDefinition, error) {
+			return OpDefinition{Extra: map[string]interface{}{"value": 1}}, nil
+		}
+		Expect(RegisterOpType(conflictingType)).To(Succeed())
+
+		_, err := NewOpDefinitionsFromOps(Ops{certOp{}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("generate-certificate operation [0]: " +
+			"Expected extra field 'value' to not conflict with definition fields"))
+	})
+
+	It("interpolates extra fields", func() {
+		path := "/cert"
+
+		opDefs, err := Interpolator{Vars: StaticVariables{"name": "example.com"}}.InterpolateOpDefinitions([]OpDefinition{
+			{Type: "generate-certificate", Path: &path, Extra: map[string]interface{}{"common_name": "((name))"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opDefs[0].Extra).To(Equal(map[string]interface{}{"common_name": "example.com"}))
+	})
+
+	It("redacts extra fields", func() {
+		path := "/cert"
+
+		opDefs, err := NewRedactedOpDefinitionsFromOps(Ops{certOp{Path: MustNewPointerFromString(path), CommonName: "ca"}},
+			RedactionPolicy{Keys: []string{"common_name"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opDefs[0].Extra).To(Equal(map[string]interface{}{"common_name": "<redacted>"}))
+	})
+
+	It("returns an error if type is already registered", func() {
+		err := RegisterOpType(certOpType)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected operation type 'generate-certificate' to not be already registered"))
+
+		otherType := certOpType
+		otherType.Name = "other"
+
+		err = RegisterOpType(otherType)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected operation 'patch_test.certOp' to not be already registered " +
+			"as operation type 'generate-certificate'"))
+	})
+
+	It("returns an error if type is built-in", func() {
+		builtinType := certOpType
+		builtinType.Name = "replace"
+
+		err := RegisterOpType(builtinType)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected operation type 'replace' to not be a built-in operation type"))
+	})
+
+	It("returns an error if type is incomplete", func() {
+		err := RegisterOpType(OpType{Name: "incomplete", New: certOpType.New})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected operation type 'incomplete' to have an example operation and a serializer"))
+	})
+
+	It("does not recognize operations after type is unregistered", func() {
+		UnregisterOpType(certOpType.Name)
+
+		_, err := NewOpsFromDefinitions([]OpDefinition{{Type: "generate-certificate"}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unknown operation [0] with type 'generate-certificate'"))
+	})
+})
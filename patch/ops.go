@@ -1,24 +1,62 @@
 package patch
 
+import (
+	"context"
+)
+
 type Ops []Op
 
 type Op interface {
 	Apply(interface{}) (interface{}, error)
 }
 
+// ContextOp is implemented by operations that could be cancelled while being applied
+type ContextOp interface {
+	Op
+	ApplyContext(context.Context, interface{}) (interface{}, error)
+}
+
 // Ensure basic operations implement Op
 var _ Op = Ops{}
 var _ Op = ReplaceOp{}
 var _ Op = RemoveOp{}
 var _ Op = FindOp{}
+var _ Op = RelativeFindOp{}
 var _ Op = DescriptiveOp{}
 var _ Op = ErrOp{}
+var _ Op = IfOp{}
+var _ Op = GroupOp{}
+var _ Op = MergeOp{}
+var _ Op = StrategicMergeOp{}
+var _ Op = ObservedOps{}
+var _ Op = ValidateOp{}
+var _ Op = ValidatedOps{}
+
+// Ensure operations that traverse documents could be cancelled
+var _ ContextOp = Ops{}
+var _ ContextOp = ReplaceOp{}
+var _ ContextOp = RemoveOp{}
+var _ ContextOp = FindOp{}
+var _ ContextOp = TestOp{}
+var _ ContextOp = DescriptiveOp{}
+var _ ContextOp = IfOp{}
+var _ ContextOp = GroupOp{}
+var _ ContextOp = MergeOp{}
+var _ ContextOp = StrategicMergeOp{}
+var _ ContextOp = ObservedOps{}
+var _ ContextOp = ValidatedOps{}
 
 func (ops Ops) Apply(doc interface{}) (interface{}, error) {
+	return ops.ApplyContext(context.Background(), doc)
+}
+
+// ApplyContext checks for cancellation before each operation;
+// operations implementing ContextOp are applied with the context
+func (ops Ops) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
 	var err error
 
 	for _, op := range ops {
-		doc, err = op.Apply(doc)
+		doc, err = applyContext(ctx, op, doc)
 		if err != nil {
 			return nil, err
 		}
@@ -26,3 +64,15 @@ func (ops Ops) Apply(doc interface{}) (interface{}, error) {
 
 	return doc, nil
 }
+
+func applyContext(ctx context.Context, op Op, doc interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if ctxOp, ok := op.(ContextOp); ok {
+		return ctxOp.ApplyContext(ctx, doc)
+	}
+
+	return op.Apply(doc)
+}
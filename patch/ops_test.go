@@ -1,7 +1,10 @@
 package patch_test
 
 import (
+	"context"
 	"errors"
+	"strconv"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -38,3 +41,76 @@ var _ = Describe("Ops.Apply", func() {
 		Expect(err.Error()).To(ContainSubstring("fake-err"))
 	})
 })
+
+type cancellingOp struct {
+	cancel context.CancelFunc
+}
+
+func (op cancellingOp) Apply(doc interface{}) (interface{}, error) { return doc, nil }
+
+func (op cancellingOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	op.cancel()
+	return doc, nil
+}
+
+var _ = Describe("Ops.ApplyContext", func() {
+	It("applies operations if context is not cancelled", func() {
+		res, err := Ops{RemoveOp{Path: MustNewPointerFromString("/0")}}.ApplyContext(context.Background(), []interface{}{1, 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal([]interface{}{2}))
+	})
+
+	It("checks for cancellation between operations", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		doc := map[interface{}]interface{}{"a": 1}
+
+		_, err := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a"), Value: 2},
+			cancellingOp{cancel},
+			ReplaceOp{Path: MustNewPointerFromString("/a"), Value: 3},
+		}.ApplyContext(ctx, doc)
+
+		Expect(err).To(Equal(context.Canceled))
+		Expect(doc).To(Equal(map[interface{}]interface{}{"a": 2}))
+	})
+
+	It("returns cancellation errors of nested operations as is", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err := Ops{
+			DescriptiveOp{Op: Ops{cancellingOp{cancel}, ErrOp{errors.New("fake-err")}}, ErrorMsg: "desc"},
+		}.ApplyContext(ctx, []interface{}{})
+		Expect(err).To(Equal(context.Canceled))
+
+		_, err = IfOp{
+			Test: TestOp{Path: MustNewPointerFromString("/0"), Absent: true},
+			Else: Ops{ErrOp{errors.New("fake-err")}},
+		}.ApplyContext(ctx, []interface{}{})
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("checks for cancellation while searching large arrays", func() {
+		var items []interface{}
+		for i := 0; i < 5000; i++ {
+			items = append(items, map[interface{}]interface{}{"name": strconv.Itoa(i)})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		_, err := ReplaceOp{Path: MustNewPointerFromString("/name=10/name"), Value: 1}.ApplyContext(ctx, items)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		_, err = GroupOp{
+			Path: MustNewPointerFromString("/name=10"),
+			Ops:  Ops{TestOp{Path: MustNewPointerFromString("/name"), Value: "10"}},
+		}.ApplyContext(ctx, items)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		_, err = FindOp{Path: MustNewPointerFromString("/name=10")}.ApplyContext(context.Background(), items)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,296 @@
+package patch
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Optimize returns shorter operations that produce the same result
+// for documents that original operations successfully apply to:
+//   - replace operations overwritten by later replace or remove operations are dropped
+//   - consecutive replace operations of non-map values under the same parent are merged
+func (ops Ops) Optimize() Ops {
+	if ops == nil {
+		return nil
+	}
+
+	o := optimizer{}
+	return o.mergeSets(o.dropShadowed(o.nested(ops)))
+}
+
+// OptimizeFor additionally drops values that are created and then removed
+// given a specific document. Result is verified by applying both versions to the document.
+func (ops Ops) OptimizeFor(doc interface{}) (Ops, error) {
+	expected, err := applyToClone(ops, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	o := optimizer{}
+
+	result := ops.Optimize()
+
+	if !o.sameResult(result, doc, expected) {
+		return ops, nil
+	}
+
+	for i := 0; i < len(result); i++ {
+		j, found := o.createdThenRemoved(result, i)
+		if !found {
+			continue
+		}
+
+		var candidate Ops
+		candidate = append(candidate, result[:i]...)
+		candidate = append(candidate, result[i+1:j]...)
+		candidate = append(candidate, result[j+1:]...)
+
+		if o.sameResult(candidate, doc, expected) {
+			result = candidate
+			i--
+		}
+	}
+
+	return result, nil
+}
+
+type optimizer struct{}
+
+func (o optimizer) nested(ops Ops) Ops {
+	result := Ops{}
+
+	for _, op := range ops {
+		switch typedOp := op.(type) {
+		case IfOp:
+			typedOp.Then = typedOp.Then.Optimize()
+			typedOp.Else = typedOp.Else.Optimize()
+			op = typedOp
+
+		case GroupOp:
+			typedOp.Ops = typedOp.Ops.Optimize()
+			op = typedOp
+
+		case Ops:
+			op = typedOp.Optimize()
+		}
+
+		result = append(result, op)
+	}
+
+	return result
+}
+
+func (o optimizer) dropShadowed(ops Ops) Ops {
+	result := Ops{}
+
+	for i, op := range ops {
+		if replaceOp, ok := op.(ReplaceOp); !ok || !o.shadowed(replaceOp, ops[i+1:]) {
+			result = append(result, op)
+		}
+	}
+
+	return result
+}
+
+// shadowed checks whether value set by operation is replaced or removed
+// by the first later operation that touches the same location
+func (o optimizer) shadowed(op ReplaceOp, later Ops) bool {
+	var a analyzer
+
+	if !o.plainSet(op.Path) {
+		return false
+	}
+
+	segs := a.segments(op.Path)
+	tokens := op.Path.Tokens()
+
+	for _, next := range later {
+		nextPath, found := opPath(next)
+		if !found {
+			return false
+		}
+
+		nextSegs := a.segments(nextPath)
+
+		if !a.hasPrefix(segs, nextSegs) && !a.hasPrefix(nextSegs, segs) {
+			if a.mayAlias(op.Path, nextPath) {
+				return false
+			}
+			continue
+		}
+
+		if !a.hasPrefix(segs, nextSegs) {
+			return false
+		}
+
+		nextTokens := nextPath.Tokens()
+		depth := len(nextTokens) - 1
+
+		switch next.(type) {
+		case ReplaceOp:
+			// parents of overwriting location must not depend on this operation
+			return o.plainSet(nextPath) && o.existedBefore(tokens, nextTokens, depth)
+
+		case RemoveOp:
+			if !o.existedBefore(tokens, nextTokens, depth) {
+				return false
+			}
+
+			// removed location itself must have existed or be optionally removed
+			return !o.optional(tokens[depth]) || o.optional(nextTokens[depth])
+
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// existedBefore checks that parents of location at depth are either
+// traversed non-optionally by original operation or optionally by next operation
+func (o optimizer) existedBefore(tokens, nextTokens []Token, depth int) bool {
+	for k := 1; k < depth; k++ {
+		if o.optional(tokens[k]) && !o.optional(nextTokens[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// plainSet checks that path sets a map key or an existing array index
+func (optimizer) plainSet(ptr Pointer) bool {
+	tokens := ptr.Tokens()
+
+	switch typedToken := tokens[len(tokens)-1].(type) {
+	case KeyToken:
+		return true
+	case IndexToken:
+		return len(typedToken.Modifiers) == 0
+	default:
+		return false
+	}
+}
+
+func (optimizer) optional(token Token) bool {
+	switch typedToken := token.(type) {
+	case KeyToken:
+		return typedToken.Optional
+	case MatchingIndexToken:
+		return typedToken.Optional
+	case MatchingValueToken:
+		return typedToken.Optional
+	default:
+		return false
+	}
+}
+
+func (o optimizer) mergeSets(ops Ops) Ops {
+	result := Ops{}
+
+	for i := 0; i < len(ops); {
+		parent, key, ok := o.mergeableSet(ops[i])
+		if !ok {
+			result = append(result, ops[i])
+			i++
+			continue
+		}
+
+		value := map[interface{}]interface{}{key: ops[i].(ReplaceOp).Value}
+		parentStr := o.normalized(parent)
+
+		j := i + 1
+
+		for ; j < len(ops); j++ {
+			nextParent, nextKey, ok := o.mergeableSet(ops[j])
+			if !ok || o.normalized(nextParent) != parentStr {
+				break
+			}
+
+			if _, found := value[nextKey]; found {
+				break
+			}
+
+			value[nextKey] = ops[j].(ReplaceOp).Value
+		}
+
+		if j-i > 1 {
+			result = append(result, MergeOp{Path: parent, Value: value})
+		} else {
+			result = append(result, ops[i])
+		}
+
+		i = j
+	}
+
+	return result
+}
+
+// mergeableSet checks that operation sets a map key to a value
+// that would be set as is by a merge operation (i.e. not null or a map)
+func (optimizer) mergeableSet(op Op) (Pointer, string, bool) {
+	replaceOp, ok := op.(ReplaceOp)
+	if !ok || replaceOp.Idempotent {
+		return Pointer{}, "", false
+	}
+
+	tokens := replaceOp.Path.Tokens()
+
+	keyToken, ok := tokens[len(tokens)-1].(KeyToken)
+	if !ok {
+		return Pointer{}, "", false
+	}
+
+	val := reflect.ValueOf(replaceOp.Value)
+	if !val.IsValid() || val.Kind() == reflect.Map {
+		return Pointer{}, "", false
+	}
+
+	return NewPointer(tokens[:len(tokens)-1]), keyToken.Key, true
+}
+
+func (optimizer) normalized(ptr Pointer) string {
+	return strings.Join(analyzer{}.segments(ptr), "/")
+}
+
+// createdThenRemoved finds a remove operation of the same location
+// that immediately follows (ignoring unrelated operations) the replace operation
+func (o optimizer) createdThenRemoved(ops Ops, i int) (int, bool) {
+	var a analyzer
+
+	replaceOp, ok := ops[i].(ReplaceOp)
+	if !ok || a.adds(replaceOp.Path) {
+		return 0, false
+	}
+
+	segs := a.segments(replaceOp.Path)
+
+	for j := i + 1; j < len(ops); j++ {
+		nextPath, found := opPath(ops[j])
+		if !found {
+			return 0, false
+		}
+
+		nextSegs := a.segments(nextPath)
+
+		if !a.hasPrefix(segs, nextSegs) && !a.hasPrefix(nextSegs, segs) {
+			if a.mayAlias(replaceOp.Path, nextPath) {
+				return 0, false
+			}
+			continue
+		}
+
+		if _, ok := ops[j].(RemoveOp); ok && len(segs) == len(nextSegs) && a.hasPrefix(segs, nextSegs) {
+			return j, true
+		}
+
+		return 0, false
+	}
+
+	return 0, false
+}
+
+func (optimizer) sameResult(ops Ops, doc, expected interface{}) bool {
+	actual, err := applyToClone(ops, doc)
+	return err == nil && reflect.DeepEqual(actual, expected)
+}
@@ -0,0 +1,187 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("Ops.Optimize", func() {
+	newDoc := func() interface{} {
+		return map[interface{}]interface{}{
+			"a": map[interface{}]interface{}{"b": 0},
+			"x": map[interface{}]interface{}{},
+		}
+	}
+
+	expectSameResult := func(ops, optimized Ops) {
+		expected, err := ops.Apply(newDoc())
+		Expect(err).ToNot(HaveOccurred())
+
+		actual, err := optimized.Apply(newDoc())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(actual).To(Equal(expected))
+	}
+
+	It("drops values that are overwritten by later operations", func() {
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a/b"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/c?"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/a"), Value: 2},
+			RemoveOp{Path: MustNewPointerFromString("/c")},
+			ReplaceOp{Path: MustNewPointerFromString("/x/d?"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/x/d?")},
+		}
+
+		optimized := ops.Optimize()
+
+		Expect(optimized).To(Equal(Ops{
+			MergeOp{Path: MustNewPointerFromString(""), Value: map[interface{}]interface{}{"a": 2, "c": 1}},
+			RemoveOp{Path: MustNewPointerFromString("/c")},
+			RemoveOp{Path: MustNewPointerFromString("/x/d?")},
+		}))
+
+		expectSameResult(ops, optimized)
+	})
+
+	It("keeps values that are used or may be required by later operations", func() {
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/a/b"), Value: 1},
+			TestOp{Path: MustNewPointerFromString("/a/b"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/a/b"), Value: 2},
+			ReplaceOp{Path: MustNewPointerFromString("/y?/z"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/y/z"), Value: 2},
+			ReplaceOp{Path: MustNewPointerFromString("/w?"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/w")},
+		}
+
+		optimized := ops.Optimize()
+
+		Expect(optimized).To(Equal(ops))
+
+		expectSameResult(ops, optimized)
+	})
+
+	It("keeps values that may be used by later operations referring to the same location differently", func() {
+		newDoc := func() interface{} {
+			return map[interface{}]interface{}{
+				"ig": []interface{}{
+					map[interface{}]interface{}{"name": "api", "instances": 0},
+					map[interface{}]interface{}{"name": "db", "instances": 0},
+					map[interface{}]interface{}{"name": "worker", "instances": 0},
+				},
+			}
+		}
+
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/ig/0/instances"), Value: 1},
+			TestOp{Path: MustNewPointerFromString("/ig/name=api/instances"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/ig/0/instances"), Value: 2},
+			ReplaceOp{Path: MustNewPointerFromString("/ig/-1/instances"), Value: 1},
+			TestOp{Path: MustNewPointerFromString("/ig/2/instances"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/ig/-1/instances")},
+		}
+
+		optimized := ops.Optimize()
+		Expect(optimized).To(Equal(ops))
+
+		_, err := optimized.Apply(newDoc())
+		Expect(err).ToNot(HaveOccurred())
+
+		optimized, err = ops.OptimizeFor(newDoc())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(optimized).To(Equal(ops))
+	})
+
+	It("merges consecutive values set under the same parent", func() {
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/x/a?"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/x?/b?"), Value: []interface{}{"s"}},
+			ReplaceOp{Path: MustNewPointerFromString("/x/c?"), Value: map[interface{}]interface{}{"d": 1}},
+			ReplaceOp{Path: MustNewPointerFromString("/x/e?"), Value: nil},
+			ReplaceOp{Path: MustNewPointerFromString("/x/f?"), Value: "f"},
+		}
+
+		optimized := ops.Optimize()
+
+		Expect(optimized).To(Equal(Ops{
+			MergeOp{
+				Path:  MustNewPointerFromString("/x"),
+				Value: map[interface{}]interface{}{"a": 1, "b": []interface{}{"s"}},
+			},
+			ReplaceOp{Path: MustNewPointerFromString("/x/c?"), Value: map[interface{}]interface{}{"d": 1}},
+			ReplaceOp{Path: MustNewPointerFromString("/x/e?"), Value: nil},
+			ReplaceOp{Path: MustNewPointerFromString("/x/f?"), Value: "f"},
+		}))
+
+		expectSameResult(ops, optimized)
+	})
+
+	It("optimizes nested operations", func() {
+		ops := Ops{
+			GroupOp{Path: MustNewPointerFromString("/a"), Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/b"), Value: 1},
+				ReplaceOp{Path: MustNewPointerFromString("/b"), Value: 2},
+			}},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/x"), Absent: true},
+				Else: Ops{
+					ReplaceOp{Path: MustNewPointerFromString("/x/a?"), Value: 1},
+					ReplaceOp{Path: MustNewPointerFromString("/x/a?"), Value: 2},
+				},
+			},
+		}
+
+		optimized := ops.Optimize()
+
+		Expect(optimized).To(Equal(Ops{
+			GroupOp{Path: MustNewPointerFromString("/a"), Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/b"), Value: 2},
+			}},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/x"), Absent: true},
+				Else: Ops{ReplaceOp{Path: MustNewPointerFromString("/x/a?"), Value: 2}},
+			},
+		}))
+
+		expectSameResult(ops, optimized)
+	})
+})
+
+var _ = Describe("Ops.OptimizeFor", func() {
+	It("drops values that are created and then removed", func() {
+		doc := map[interface{}]interface{}{"x": map[interface{}]interface{}{}}
+
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/x/tmp?"), Value: 1},
+			ReplaceOp{Path: MustNewPointerFromString("/y?"), Value: 2},
+			RemoveOp{Path: MustNewPointerFromString("/x/tmp")},
+		}
+
+		optimized, err := ops.OptimizeFor(doc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(optimized).To(Equal(Ops{ReplaceOp{Path: MustNewPointerFromString("/y?"), Value: 2}}))
+
+		Expect(doc).To(Equal(map[interface{}]interface{}{"x": map[interface{}]interface{}{}}))
+	})
+
+	It("keeps removal of values that existed in the document", func() {
+		doc := map[interface{}]interface{}{"tmp": 0}
+
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/tmp?"), Value: 1},
+			RemoveOp{Path: MustNewPointerFromString("/tmp")},
+		}
+
+		optimized, err := ops.OptimizeFor(doc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(optimized).To(Equal(ops))
+	})
+
+	It("returns an error if operations cannot be applied", func() {
+		_, err := Ops{RemoveOp{Path: MustNewPointerFromString("/missing")}}.OptimizeFor(map[interface{}]interface{}{})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -90,6 +90,18 @@ func NewPointerFromString(str string) (Pointer, error) {
 		// parse name=val
 		kv := strings.SplitN(tok, "=", 2)
 		if len(kv) == 2 {
+			// parse =val
+			if len(kv[0]) == 0 {
+				token := MatchingValueToken{
+					Value:     strings.TrimSuffix(kv[1], "?"),
+					Optional:  optional,
+					Modifiers: modifiers,
+				}
+
+				tokens = append(tokens, token)
+				continue
+			}
+
 			token := MatchingIndexToken{
 				Key:       kv[0],
 				Value:     strings.TrimSuffix(kv[1], "?"),
@@ -132,6 +144,52 @@ func NewPointer(tokens []Token) Pointer {
 
 func (p Pointer) Tokens() []Token { return p.tokens }
 
+// Concat returns a pointer that refers to other pointer relative to this pointer.
+// Similarly to parsed pointers, optionality carries over to the appended tokens.
+// Unset pointers are treated as root pointers.
+func (p Pointer) Concat(other Pointer) Pointer {
+	if !p.IsSet() {
+		p = NewPointer([]Token{RootToken{}})
+	}
+
+	if !other.IsSet() {
+		other = NewPointer([]Token{RootToken{}})
+	}
+
+	tokens := append([]Token{}, p.tokens...)
+
+	optional := false
+
+	for _, token := range p.tokens {
+		switch typedToken := token.(type) {
+		case KeyToken:
+			optional = optional || typedToken.Optional
+		case MatchingIndexToken:
+			optional = optional || typedToken.Optional
+		case MatchingValueToken:
+			optional = optional || typedToken.Optional
+		}
+	}
+
+	for _, token := range other.tokens[1:] {
+		switch typedToken := token.(type) {
+		case KeyToken:
+			typedToken.Optional = optional || typedToken.Optional
+			token = typedToken
+		case MatchingIndexToken:
+			typedToken.Optional = optional || typedToken.Optional
+			token = typedToken
+		case MatchingValueToken:
+			typedToken.Optional = optional || typedToken.Optional
+			token = typedToken
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return Pointer{tokens}
+}
+
 func (p Pointer) IsSet() bool { return len(p.tokens) > 0 }
 
 func (p Pointer) String() string {
@@ -163,6 +221,18 @@ func (p Pointer) String() string {
 
 			strs = append(strs, fmt.Sprintf("%s=%s%s", key, val, p.modifiersString(typedToken.Modifiers)))
 
+		case MatchingValueToken:
+			val := rfc6901Encoder.Replace(typedToken.Value)
+
+			if typedToken.Optional {
+				if !optional {
+					val += "?"
+					optional = true
+				}
+			}
+
+			strs = append(strs, fmt.Sprintf("=%s%s", val, p.modifiersString(typedToken.Modifiers)))
+
 		case KeyToken:
 			str := rfc6901Encoder.Replace(typedToken.Key)
 
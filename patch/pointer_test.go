@@ -67,11 +67,7 @@ var testCases = []PointerTestCase{
 		MatchingIndexToken{Key: "name", Value: "val", Optional: true},
 		MatchingIndexToken{Key: "name2", Value: "val", Optional: true},
 	}},
-	{"/=", []Token{RootToken{}, MatchingIndexToken{Key: "", Value: ""}}},
-	{"/=?", []Token{RootToken{}, MatchingIndexToken{Key: "", Value: "", Optional: true}}},
 	{"/name=", []Token{RootToken{}, MatchingIndexToken{Key: "name", Value: ""}}},
-	{"/=val", []Token{RootToken{}, MatchingIndexToken{Key: "", Value: "val"}}},
-	{"/==", []Token{RootToken{}, MatchingIndexToken{Key: "", Value: "="}}},
 
 	{"/name=val:before", []Token{
 		RootToken{},
@@ -82,6 +78,21 @@ var testCases = []PointerTestCase{
 		MatchingIndexToken{Key: "name", Value: "val", Modifiers: []Modifier{AfterModifier{}}},
 	}},
 
+	// Matching value token
+	{"/=", []Token{RootToken{}, MatchingValueToken{Value: ""}}},
+	{"/=?", []Token{RootToken{}, MatchingValueToken{Value: "", Optional: true}}},
+	{"/=val", []Token{RootToken{}, MatchingValueToken{Value: "val"}}},
+	{"/==", []Token{RootToken{}, MatchingValueToken{Value: "="}}},
+	{"/key?/=val", []Token{
+		RootToken{},
+		KeyToken{Key: "key", Optional: true},
+		MatchingValueToken{Value: "val", Optional: true},
+	}},
+	{"/=val:after", []Token{
+		RootToken{},
+		MatchingValueToken{Value: "val", Modifiers: []Modifier{AfterModifier{}}},
+	}},
+
 	// Optionality
 	{"/key?/name=val", []Token{
 		RootToken{},
@@ -177,6 +188,49 @@ var _ = Describe("Pointer.Tokens", func() {
 	}
 })
 
+var _ = Describe("Pointer.Concat", func() {
+	It("appends tokens of other pointer", func() {
+		ptr := MustNewPointerFromString("/instance_groups/name=api").Concat(MustNewPointerFromString("/jobs/0:next"))
+		Expect(ptr.String()).To(Equal("/instance_groups/name=api/jobs/0:next"))
+		Expect(ptr.Tokens()).To(Equal([]Token{
+			RootToken{},
+			KeyToken{Key: "instance_groups"},
+			MatchingIndexToken{Key: "name", Value: "api"},
+			KeyToken{Key: "jobs"},
+			IndexToken{Index: 0, Modifiers: []Modifier{NextModifier{}}},
+		}))
+	})
+
+	It("returns same pointer when concatenated with root pointer", func() {
+		Expect(MustNewPointerFromString("/a").Concat(MustNewPointerFromString(""))).To(Equal(MustNewPointerFromString("/a")))
+		Expect(MustNewPointerFromString("").Concat(MustNewPointerFromString("/a"))).To(Equal(MustNewPointerFromString("/a")))
+	})
+
+	It("treats unset pointers as root pointers", func() {
+		Expect(MustNewPointerFromString("/a").Concat(Pointer{})).To(Equal(MustNewPointerFromString("/a")))
+		Expect(Pointer{}.Concat(MustNewPointerFromString("/a"))).To(Equal(MustNewPointerFromString("/a")))
+	})
+
+	It("carries over optionality to appended tokens", func() {
+		ptr := MustNewPointerFromString("/a?").Concat(MustNewPointerFromString("/b/name=c"))
+		Expect(ptr.String()).To(Equal("/a?/b/name=c"))
+		Expect(ptr).To(Equal(MustNewPointerFromString("/a?/b/name=c")))
+
+		ptr = MustNewPointerFromString("/a").Concat(MustNewPointerFromString("/b?/c"))
+		Expect(ptr).To(Equal(MustNewPointerFromString("/a/b?/c")))
+	})
+
+	It("does not modify original pointers", func() {
+		base := MustNewPointerFromString("/a?")
+		other := MustNewPointerFromString("/b")
+
+		base.Concat(other)
+
+		Expect(base).To(Equal(MustNewPointerFromString("/a?")))
+		Expect(other).To(Equal(MustNewPointerFromString("/b")))
+	})
+})
+
 var _ = Describe("Pointer.IsSet", func() {
 	It("returns true if there is at least one token", func() {
 		Expect(MustNewPointerFromString("").IsSet()).To(BeTrue())
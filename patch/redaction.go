@@ -0,0 +1,193 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+)
+
+const redactedValue = "<redacted>"
+
+// RedactionPolicy determines which values are hidden from error messages, explanations and reports
+type RedactionPolicy struct {
+	Paths []Pointer // patterns with the same syntax as Diff.Ignore (ex: /**/password)
+	Keys  []string  // map keys equal to or ending with '_' or '-' followed by any of these names, case insensitive (ex: password matches db_password)
+}
+
+// DefaultRedactionPolicy returns policy used by error messages
+func DefaultRedactionPolicy() RedactionPolicy {
+	return RedactionPolicy{
+		Keys: []string{"password", "passwords", "passphrase", "secret", "secrets", "private_key", "token", "tokens", "credentials"},
+	}
+}
+
+// Redacts returns true if value at the path (or one of its parents) should be hidden
+func (p RedactionPolicy) Redacts(path Pointer, val interface{}) bool {
+	nodes := p.nodes(path)
+	if len(nodes) > 0 {
+		nodes[len(nodes)-1].left, nodes[len(nodes)-1].leftFound = val, true
+	}
+	return p.matches(nodes)
+}
+
+// Redact returns value with hidden values replaced by '<redacted>';
+// value is not copied if there is nothing to hide
+func (p RedactionPolicy) Redact(path Pointer, val interface{}) interface{} {
+	if p.Redacts(path, val) {
+		return redactedValue
+	}
+
+	result, _ := p.redact(p.nodes(path), val)
+	return result
+}
+
+// fmtValue formats value for error messages
+func (p RedactionPolicy) fmtValue(path Pointer, val interface{}) string {
+	return formatChangeValue(p.Redact(path, val))
+}
+
+func (p RedactionPolicy) redact(nodes []diffNode, val interface{}) (interface{}, bool) {
+	child := func(token Token, val interface{}) (interface{}, bool) {
+		childNodes := append(append([]diffNode{}, nodes...), diffNode{token: token, left: val, leftFound: true})
+		if p.matches(childNodes) {
+			return redactedValue, true
+		}
+		return p.redact(childNodes, val)
+	}
+
+	switch typedVal := val.(type) {
+	case map[interface{}]interface{}:
+		result := map[interface{}]interface{}{}
+		var redacted bool
+
+		for k, v := range typedVal {
+			childVal, childRedacted := child(KeyToken{Key: fmt.Sprintf("%v", k)}, v)
+			result[k] = childVal
+			redacted = redacted || childRedacted
+		}
+
+		if !redacted {
+			return val, false
+		}
+		return result, true
+
+	case []interface{}:
+		result := []interface{}{}
+		var redacted bool
+
+		for i, v := range typedVal {
+			childVal, childRedacted := child(IndexToken{Index: i}, v)
+			result = append(result, childVal)
+			redacted = redacted || childRedacted
+		}
+
+		if !redacted {
+			return val, false
+		}
+		return result, true
+
+	default:
+		return val, false
+	}
+}
+
+func (p RedactionPolicy) matches(nodes []diffNode) bool {
+	for _, node := range nodes {
+		if typedToken, ok := node.token.(KeyToken); ok {
+			key := strings.ToLower(typedToken.Key)
+			for _, sensitiveKey := range p.Keys {
+				sensitiveKey = strings.ToLower(sensitiveKey)
+				if key == sensitiveKey || strings.HasSuffix(key, "_"+sensitiveKey) || strings.HasSuffix(key, "-"+sensitiveKey) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, pattern := range p.Paths {
+		if matched, _ := matchPattern(pattern.Tokens()[1:], nodes); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (RedactionPolicy) nodes(path Pointer) []diffNode {
+	var nodes []diffNode
+
+	for _, token := range path.Tokens()[1:] {
+		nodes = append(nodes, diffNode{token: token})
+	}
+
+	return nodes
+}
+
+// NewRedactedOpDefinitionsFromOps returns definitions with values hidden according to the policy
+// (ex: to log operations); paths of nested group operations are considered to be relative
+func NewRedactedOpDefinitionsFromOps(ops Ops, policy RedactionPolicy) ([]OpDefinition, error) {
+	opDefs, err := NewOpDefinitionsFromOps(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy.redactOpDefs(opDefs, nil), nil
+}
+
+func (p RedactionPolicy) redactOpDefs(opDefs []OpDefinition, base []Token) []OpDefinition {
+	var result []OpDefinition
+
+	for _, opDef := range opDefs {
+		result = append(result, p.redactOpDef(opDef, base))
+	}
+
+	return result
+}
+
+func (p RedactionPolicy) redactOpDef(opDef OpDefinition, base []Token) OpDefinition {
+	tokens := base
+
+	if opDef.Path != nil {
+		if ptr, err := NewPointerFromString(*opDef.Path); err == nil {
+			tokens = append(append([]Token{}, base...), ptr.Tokens()[1:]...)
+		}
+	}
+
+	path := NewPointer(append([]Token{RootToken{}}, tokens...))
+
+	if opDef.Value != nil {
+		val := p.Redact(path, *opDef.Value)
+		opDef.Value = &val
+	}
+
+	if opDef.Contains != nil {
+		if contains, ok := p.Redact(path, opDef.Contains).([]interface{}); ok {
+			opDef.Contains = contains
+		} else {
+			opDef.Contains = []interface{}{redactedValue}
+		}
+	}
+
+	if opDef.Extra != nil {
+		extra := map[string]interface{}{}
+		for key, val := range opDef.Extra {
+			// extra fields are treated as children of the path (ex: to hide 'private_key' fields)
+			extra[key] = p.Redact(NewPointer(append(append([]Token{}, path.Tokens()...), KeyToken{Key: key})), val)
+		}
+		opDef.Extra = extra
+	}
+
+	if opDef.Test != nil {
+		test := p.redactOpDef(*opDef.Test, base)
+		opDef.Test = &test
+	}
+
+	if opDef.Type == "group" {
+		opDef.Ops = p.redactOpDefs(opDef.Ops, tokens)
+	} else {
+		opDef.Ops = p.redactOpDefs(opDef.Ops, base)
+	}
+
+	opDef.Else = p.redactOpDefs(opDef.Else, base)
+
+	return opDef
+}
@@ -0,0 +1,184 @@
+package patch_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("RedactionPolicy", func() {
+	policy := RedactionPolicy{
+		Paths: []Pointer{
+			MustNewPointerFromString("/**/ca/private"),
+			MustNewPointerFromString("/instance_groups/name=db/env"),
+		},
+		Keys: []string{"Password"},
+	}
+
+	It("redacts values at matching paths or keys", func() {
+		Expect(policy.Redacts(MustNewPointerFromString("/admin_password"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/db-PASSWORD/0"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/password_length"), "x")).To(BeFalse())
+		Expect(policy.Redacts(MustNewPointerFromString("/passwords"), "x")).To(BeFalse())
+		Expect(policy.Redacts(MustNewPointerFromString("/a/b/ca/private"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/instance_groups/name=db/env/x"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/instance_groups/name=api/env"), "x")).To(BeFalse())
+		Expect(policy.Redacts(MustNewPointerFromString("/ca/certificate"), "x")).To(BeFalse())
+		Expect(policy.Redacts(MustNewPointerFromString(""), "x")).To(BeFalse())
+	})
+
+	It("redacts nested values", func() {
+		val := map[interface{}]interface{}{
+			"ca": map[interface{}]interface{}{"certificate": "cert", "private": "key"},
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{"name": "api", "env": map[interface{}]interface{}{"a": 1}},
+				map[interface{}]interface{}{"name": "db", "env": map[interface{}]interface{}{"a": 1}},
+			},
+			"users": []interface{}{map[interface{}]interface{}{"name": "admin", "password": "pass"}},
+		}
+
+		Expect(policy.Redact(MustNewPointerFromString(""), val)).To(Equal(map[interface{}]interface{}{
+			"ca": map[interface{}]interface{}{"certificate": "cert", "private": "<redacted>"},
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{"name": "api", "env": map[interface{}]interface{}{"a": 1}},
+				map[interface{}]interface{}{"name": "db", "env": "<redacted>"},
+			},
+			"users": []interface{}{map[interface{}]interface{}{"name": "admin", "password": "<redacted>"}},
+		}))
+
+		// original value is not modified
+		Expect(val["users"]).To(Equal([]interface{}{map[interface{}]interface{}{"name": "admin", "password": "pass"}}))
+
+		Expect(policy.Redact(MustNewPointerFromString("/users/0/password"), "pass")).To(Equal("<redacted>"))
+		Expect(policy.Redact(MustNewPointerFromString("/users/0"), map[interface{}]interface{}{"password": "pass"})).To(
+			Equal(map[interface{}]interface{}{"password": "<redacted>"}))
+		Expect(policy.Redact(MustNewPointerFromString("/users"), []interface{}{"a"})).To(Equal([]interface{}{"a"}))
+	})
+
+	It("hides values of sensitive keys by default", func() {
+		policy := DefaultRedactionPolicy()
+
+		Expect(policy.Redacts(MustNewPointerFromString("/access_token"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/ssh/private_key"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/credentials/user"), "x")).To(BeTrue())
+		Expect(policy.Redacts(MustNewPointerFromString("/token_url"), "x")).To(BeFalse())
+		Expect(policy.Redacts(MustNewPointerFromString("/tokens_per_minute"), "x")).To(BeFalse())
+	})
+
+	It("redacts values by default in error messages", func() {
+		doc := map[interface{}]interface{}{"creds": map[interface{}]interface{}{"user": "admin", "password": "pass"}}
+
+		_, err := TestOp{
+			Path:  MustNewPointerFromString("/creds"),
+			Value: map[interface{}]interface{}{"user": "root", "password": "other-pass"},
+		}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`Found value at path '/creds' does not match expected value:
+  /creds/password: expected "<redacted>", found "<redacted>"
+  /creds/user: expected "root", found "admin"`))
+
+		_, err = TestOp{Path: MustNewPointerFromString("/creds/password"), Length: new(int)}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`Expected value at path '/creds/password' to have length 0 but found length 4 ("<redacted>")`))
+
+		_, err = TestOp{Path: MustNewPointerFromString("/creds"), Is: "array"}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`Expected value at path '/creds' to be of type 'array' but found {"password":"<redacted>","user":"admin"} of type 'object'`))
+
+		_, err = ReplaceOp{Path: MustNewPointerFromString("/creds/password/0"), Value: 1}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find an array at path '/creds/password/0' but found 'string'"))
+		Expect(err.(OpMismatchTypeErr).Obj).To(Equal("pass"))
+
+		_, err = ReplaceOp{Path: MustNewPointerFromString("/creds/0"), Value: 1}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(OpMismatchTypeErr).Obj).To(Equal(map[interface{}]interface{}{"user": "admin", "password": "pass"}))
+	})
+
+	It("redacts values by default in schema violations", func() {
+		schema := Schema{Definition: map[interface{}]interface{}{
+			"properties": map[interface{}]interface{}{
+				"password": map[interface{}]interface{}{"pattern": "^[0-9]+$"},
+			},
+		}}
+
+		violations := schema.Validate(map[interface{}]interface{}{"password": "pass"})
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].String()).To(Equal(`'/password': Expected string to match pattern '^[0-9]+$' but found "<redacted>"`))
+	})
+
+	It("redacts explanations with given policy", func() {
+		_, changes, err := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/password"), Value: "new-pass"},
+		}.Explain(map[interface{}]interface{}{"password": "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes.String()).To(Equal(`op 0: replaced /password "pass" → "new-pass"`))
+
+		redacted := changes.Redact(DefaultRedactionPolicy())
+		Expect(redacted.String()).To(Equal(`op 0: replaced /password "<redacted>" → "<redacted>"`))
+		Expect(changes[0].New).To(Equal("new-pass"))
+
+		bs, err := json.Marshal(redacted)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bs)).ToNot(ContainSubstring("pass\""))
+	})
+})
+
+var _ = Describe("NewRedactedOpDefinitionsFromOps", func() {
+	It("redacts values of operations", func() {
+		ops := Ops{
+			ReplaceOp{Path: MustNewPointerFromString("/users/name=admin"), Value: map[interface{}]interface{}{"name": "admin", "password": "pass"}},
+			TestOp{Path: MustNewPointerFromString("/token"), Value: "t"},
+			GroupOp{
+				Path: MustNewPointerFromString("/instance_groups/name=db"),
+				Ops:  Ops{ReplaceOp{Path: MustNewPointerFromString("/env"), Value: "secret-env"}},
+			},
+			IfOp{
+				Test: TestOp{Path: MustNewPointerFromString("/db_password"), Value: "old"},
+				Then: Ops{ReplaceOp{Path: MustNewPointerFromString("/db_password"), Value: "new"}},
+			},
+		}
+
+		opDefs, err := NewRedactedOpDefinitionsFromOps(ops, RedactionPolicy{
+			Paths: []Pointer{MustNewPointerFromString("/instance_groups/name=db/env")},
+			Keys:  []string{"password", "token"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		bs, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect("\n" + string(bs)).To(Equal(`
+- type: replace
+  path: /users/name=admin
+  value:
+    name: admin
+    password: <redacted>
+- type: test
+  path: /token
+  value: <redacted>
+- type: group
+  path: /instance_groups/name=db
+  ops:
+  - type: replace
+    path: /env
+    value: <redacted>
+- type: if
+  test:
+    type: test
+    path: /db_password
+    value: <redacted>
+  ops:
+  - type: replace
+    path: /db_password
+    value: <redacted>
+`))
+
+		// operations are not modified
+		Expect(ops[0].(ReplaceOp).Value).To(HaveKeyWithValue("password", "pass"))
+	})
+})
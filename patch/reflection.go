@@ -1,9 +1,14 @@
 package patch
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 )
 
+// cancelCheckInterval limits how often long traversals check for cancellation
+const cancelCheckInterval = 1024
+
 func dereference(v reflect.Value) reflect.Value {
 	switch v.Kind() {
 	case reflect.Interface, reflect.Ptr:
@@ -14,9 +19,20 @@ func dereference(v reflect.Value) reflect.Value {
 }
 
 func findMapIndices(sliceOfMaps reflect.Value, key, value interface{}) []int {
+	idxs, _ := findMapIndicesContext(context.Background(), sliceOfMaps, key, value)
+	return idxs
+}
+
+func findMapIndicesContext(ctx context.Context, sliceOfMaps reflect.Value, key, value interface{}) ([]int, error) {
 	var idxs []int
 
 	for itemIdx := 0; itemIdx < sliceOfMaps.Len(); itemIdx++ {
+		if itemIdx%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		item := dereference(sliceOfMaps.Index(itemIdx))
 
 		if item.Kind() != reflect.Map {
@@ -37,5 +53,50 @@ func findMapIndices(sliceOfMaps reflect.Value, key, value interface{}) []int {
 		idxs = append(idxs, itemIdx)
 	}
 
+	return idxs, nil
+}
+
+// findValueIndices matches scalar items by their string representation
+// (ex: '1' matches both 1 and "1"); other items (ex: maps or nulls) never match
+func findValueIndices(slice reflect.Value, value string) []int {
+	idxs, _ := findValueIndicesContext(context.Background(), slice, value)
 	return idxs
-}
\ No newline at end of file
+}
+
+func findValueIndicesContext(ctx context.Context, slice reflect.Value, value string) ([]int, error) {
+	var idxs []int
+
+	for itemIdx := 0; itemIdx < slice.Len(); itemIdx++ {
+		if itemIdx%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		item := dereference(slice.Index(itemIdx))
+
+		if !isScalar(item) {
+			continue
+		}
+
+		if fmt.Sprintf("%v", item.Interface()) != value {
+			continue
+		}
+
+		idxs = append(idxs, itemIdx)
+	}
+
+	return idxs, nil
+}
+
+func isScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
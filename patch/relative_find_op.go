@@ -0,0 +1,76 @@
+package patch
+
+import (
+	"reflect"
+)
+
+// RelativeFindOp evaluates relative pointer against the document at Base location
+type RelativeFindOp struct {
+	Base Pointer
+	Path RelativePointer
+}
+
+func (op RelativeFindOp) Apply(doc interface{}) (interface{}, error) {
+	ptr, err := op.Path.Resolve(op.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := FindOp{Path: ptr}.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !op.Path.Hash {
+		return found, nil
+	}
+
+	tokens := ptr.Tokens()
+	parentPtr := NewPointer(tokens[:len(tokens)-1])
+
+	switch typedToken := tokens[len(tokens)-1].(type) {
+	case KeyToken:
+		return typedToken.Key, nil
+
+	case IndexToken:
+		parent, err := FindOp{Path: parentPtr}.Apply(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return ArrayIndex{Index: typedToken.Index, Modifiers: typedToken.Modifiers, Array: reflect.ValueOf(parent), Path: ptr}.Concrete()
+
+	case MatchingIndexToken:
+		parent, err := FindOp{Path: parentPtr}.Apply(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		array := reflect.ValueOf(parent)
+
+		idxs := findMapIndices(array, typedToken.Key, typedToken.Value)
+		if len(idxs) != 1 {
+			return nil, NewOpMultipleMatchingIndexErr(ptr, idxs, array)
+		}
+
+		return ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: array, Path: ptr}.Concrete()
+
+	case MatchingValueToken:
+		parent, err := FindOp{Path: parentPtr}.Apply(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		array := reflect.ValueOf(parent)
+
+		idxs := findValueIndices(array, typedToken.Value)
+		if len(idxs) != 1 {
+			return nil, NewOpMultipleMatchingIndexErr(ptr, idxs, array)
+		}
+
+		return ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: array, Path: ptr}.Concrete()
+
+	default:
+		return nil, OpUnexpectedTokenErr{Token: typedToken, Path: ptr}
+	}
+}
@@ -0,0 +1,91 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("RelativeFindOp.Apply", func() {
+	doc := map[interface{}]interface{}{
+		"foo": []interface{}{"bar", "baz"},
+		"highly": map[interface{}]interface{}{
+			"nested": map[interface{}]interface{}{"objects": true},
+		},
+		"items": []interface{}{
+			map[interface{}]interface{}{"name": "a", "val": 1},
+			map[interface{}]interface{}{"name": "b", "val": 2},
+		},
+	}
+
+	find := func(base, rel string) (interface{}, error) {
+		return RelativeFindOp{
+			Base: MustNewPointerFromString(base),
+			Path: MustNewRelativePointerFromString(rel),
+		}.Apply(doc)
+	}
+
+	It("finds values relative to base location", func() {
+		res, err := find("/foo/1", "0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal("baz"))
+
+		res, err = find("/foo/1", "1/0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal("bar"))
+
+		res, err = find("/foo/1", "2/highly/nested/objects")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(true))
+
+		res, err = find("/highly/nested", "0/objects")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(true))
+
+		res, err = find("/items/name=a/val", "2/name=b/val")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(2))
+	})
+
+	It("finds keys and indexes for hash", func() {
+		res, err := find("/foo/1", "0#")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(1))
+
+		res, err = find("/foo/-1", "0#")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(1))
+
+		res, err = find("/foo/1", "1#")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal("foo"))
+
+		res, err = find("/highly/nested/objects", "2#")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal("highly"))
+
+		res, err = find("/items/name=b/val", "1#")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(1))
+
+		res, err = find("/foo/=baz", "0#")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(1))
+	})
+
+	It("returns an error if location cannot be found", func() {
+		_, err := find("/foo/1", "1/2")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find array index '2' but found array of length '2' for path '/foo/2' (valid indices are 0 to 1 or -2 to -1)"))
+
+		_, err = find("/highly/nested", "0/missing#")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error if pointer cannot be resolved", func() {
+		_, err := find("/foo", "2")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to go up at most '1' levels from '/foo' but was asked to go up '2' levels"))
+	})
+})
@@ -0,0 +1,115 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RelativePointer is based on https://tools.ietf.org/html/draft-handrews-relative-json-pointer-01
+// (e.g. '0/foo', '1/bar', '2#'). Remaining pointer uses the same syntax as Pointer.
+type RelativePointer struct {
+	// Up is the number of levels to go up from the current location
+	Up int
+
+	// Pointer is evaluated relative to the location reached after going up
+	Pointer Pointer
+
+	// Hash refers to the key or index of the location reached after going up
+	Hash bool
+}
+
+func MustNewRelativePointerFromString(str string) RelativePointer {
+	ptr, err := NewRelativePointerFromString(str)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return ptr
+}
+
+func NewRelativePointerFromString(str string) (RelativePointer, error) {
+	numEnd := strings.IndexFunc(str, func(r rune) bool { return r < '0' || r > '9' })
+	if numEnd == -1 {
+		numEnd = len(str)
+	}
+
+	if numEnd == 0 {
+		return RelativePointer{}, fmt.Errorf("Expected to start with a non-negative integer")
+	}
+
+	if numEnd > 1 && str[0] == '0' {
+		return RelativePointer{}, fmt.Errorf("Expected to not find leading zeros")
+	}
+
+	up, err := strconv.Atoi(str[:numEnd])
+	if err != nil {
+		return RelativePointer{}, fmt.Errorf("Expected to start with a non-negative integer: %s", err)
+	}
+
+	rest := str[numEnd:]
+
+	if rest == "#" {
+		return RelativePointer{Up: up, Pointer: Pointer{[]Token{RootToken{}}}, Hash: true}, nil
+	}
+
+	ptr, err := NewPointerFromString(rest)
+	if err != nil {
+		return RelativePointer{}, err
+	}
+
+	return RelativePointer{Up: up, Pointer: ptr}, nil
+}
+
+// Resolve returns a pointer relative to the base location.
+// For pointers ending with '#', location whose key or index is requested is returned.
+func (p RelativePointer) Resolve(base Pointer) (Pointer, error) {
+	baseTokens := base.Tokens()
+
+	if len(baseTokens) == 0 {
+		return Pointer{}, fmt.Errorf("Expected base pointer to be set")
+	}
+
+	if p.Up > len(baseTokens)-1 {
+		return Pointer{}, fmt.Errorf("Expected to go up at most '%d' levels from '%s' but was asked to go up '%d' levels", len(baseTokens)-1, base, p.Up)
+	}
+
+	ptr := NewPointer(baseTokens[:len(baseTokens)-p.Up])
+
+	if p.Hash {
+		if len(ptr.Tokens()) == 1 {
+			return Pointer{}, fmt.Errorf("Expected to not find '#' referring to document root")
+		}
+		return ptr, nil
+	}
+
+	for _, token := range ptr.Tokens() {
+		if _, ok := token.(AfterLastIndexToken); ok {
+			return Pointer{}, fmt.Errorf("Expected to not find after last index token in base location '%s'", ptr)
+		}
+	}
+
+	return ptr.Concat(p.Pointer), nil
+}
+
+func (p RelativePointer) String() string {
+	if p.Hash {
+		return fmt.Sprintf("%d#", p.Up)
+	}
+	if !p.Pointer.IsSet() {
+		return fmt.Sprintf("%d", p.Up)
+	}
+	return fmt.Sprintf("%d%s", p.Up, p.Pointer)
+}
+
+// UnmarshalFlag satisfies go-flags flag interface
+func (p *RelativePointer) UnmarshalFlag(data string) error {
+	ptr, err := NewRelativePointerFromString(data)
+	if err != nil {
+		return err
+	}
+
+	*p = ptr
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("NewRelativePointerFromString", func() {
+	It("parses number of levels and pointer", func() {
+		ptr := MustNewRelativePointerFromString("0")
+		Expect(ptr).To(Equal(RelativePointer{Up: 0, Pointer: MustNewPointerFromString("")}))
+
+		ptr = MustNewRelativePointerFromString("1/foo/0")
+		Expect(ptr).To(Equal(RelativePointer{Up: 1, Pointer: MustNewPointerFromString("/foo/0")}))
+
+		ptr = MustNewRelativePointerFromString("12/name=val?/key")
+		Expect(ptr).To(Equal(RelativePointer{Up: 12, Pointer: MustNewPointerFromString("/name=val?/key")}))
+	})
+
+	It("parses hash", func() {
+		ptr := MustNewRelativePointerFromString("2#")
+		Expect(ptr).To(Equal(RelativePointer{Up: 2, Pointer: MustNewPointerFromString(""), Hash: true}))
+	})
+
+	It("returns an error if number of levels is missing", func() {
+		_, err := NewRelativePointerFromString("/foo")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to start with a non-negative integer"))
+
+		_, err = NewRelativePointerFromString("")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to start with a non-negative integer"))
+
+		_, err = NewRelativePointerFromString("-1/foo")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to start with a non-negative integer"))
+	})
+
+	It("returns an error if number of levels has leading zeros", func() {
+		_, err := NewRelativePointerFromString("01/foo")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to not find leading zeros"))
+	})
+
+	It("returns an error if pointer is invalid", func() {
+		_, err := NewRelativePointerFromString("0foo")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to start with '/'"))
+
+		_, err = NewRelativePointerFromString("0#/foo")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to start with '/'"))
+	})
+})
+
+var _ = Describe("RelativePointer.String", func() {
+	It("returns original string", func() {
+		for _, str := range []string{"0", "1/foo/0", "2#", "3/name=val?/key", "0/"} {
+			Expect(MustNewRelativePointerFromString(str).String()).To(Equal(str))
+		}
+	})
+})
+
+var _ = Describe("RelativePointer.Resolve", func() {
+	base := MustNewPointerFromString("/foo/1")
+
+	It("goes up and appends pointer", func() {
+		ptr, err := MustNewRelativePointerFromString("0").Resolve(base)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ptr.String()).To(Equal("/foo/1"))
+
+		ptr, err = MustNewRelativePointerFromString("1/0").Resolve(base)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ptr.String()).To(Equal("/foo/0"))
+
+		ptr, err = MustNewRelativePointerFromString("2/highly/nested/objects").Resolve(base)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ptr.String()).To(Equal("/highly/nested/objects"))
+
+		ptr, err = MustNewRelativePointerFromString("0/name=val").Resolve(base)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ptr.String()).To(Equal("/foo/1/name=val"))
+	})
+
+	It("returns location for hash", func() {
+		ptr, err := MustNewRelativePointerFromString("0#").Resolve(base)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ptr.String()).To(Equal("/foo/1"))
+
+		ptr, err = MustNewRelativePointerFromString("1#").Resolve(base)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ptr.String()).To(Equal("/foo"))
+	})
+
+	It("returns an error if going up past document root", func() {
+		_, err := MustNewRelativePointerFromString("3/foo").Resolve(base)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to go up at most '2' levels from '/foo/1' but was asked to go up '3' levels"))
+	})
+
+	It("returns an error if hash refers to document root", func() {
+		_, err := MustNewRelativePointerFromString("2#").Resolve(base)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to not find '#' referring to document root"))
+	})
+
+	It("returns an error if base is not set", func() {
+		_, err := MustNewRelativePointerFromString("0").Resolve(Pointer{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected base pointer to be set"))
+	})
+})
@@ -1,7 +1,7 @@
 package patch
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 )
 
@@ -10,10 +10,18 @@ type RemoveOp struct {
 }
 
 func (op RemoveOp) Apply(doc interface{}) (interface{}, error) {
+	return op.apply(context.Background(), doc)
+}
+
+func (op RemoveOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	return op.apply(ctx, doc)
+}
+
+func (op RemoveOp) apply(ctx context.Context, doc interface{}) (interface{}, error) {
 	tokens := op.Path.Tokens()
 
 	if len(tokens) == 1 {
-		return nil, fmt.Errorf("Cannot remove entire document")
+		return nil, OpRemoveDocumentErr{Path: op.Path}
 	}
 
 	obj := doc
@@ -54,14 +62,17 @@ func (op RemoveOp) Apply(doc interface{}) (interface{}, error) {
 				return nil, NewOpArrayMismatchTypeErr(currPath, obj)
 			}
 
-			idxs := findMapIndices(ptr, typedToken.Key, typedToken.Value)
+			idxs, err := findMapIndicesContext(ctx, ptr, typedToken.Key, typedToken.Value)
+			if err != nil {
+				return nil, err
+			}
 
 			if typedToken.Optional && len(idxs) == 0 {
 				return doc, nil
 			}
 
 			if len(idxs) != 1 {
-				return nil, OpMultipleMatchingIndexErr{currPath, idxs}
+				return nil, NewOpMultipleMatchingIndexErr(currPath, idxs, ptr)
 			}
 
 			idx, err := ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
@@ -79,6 +90,42 @@ func (op RemoveOp) Apply(doc interface{}) (interface{}, error) {
 				// no need to change prevUpdate since matching item can only be a map
 			}
 
+		case MatchingValueToken:
+			ptr := reflect.ValueOf(obj)
+			if ptr.Kind() != reflect.Slice {
+				return nil, NewOpArrayMismatchTypeErr(currPath, obj)
+			}
+
+			idxs, err := findValueIndicesContext(ctx, ptr, typedToken.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			if typedToken.Optional && len(idxs) == 0 {
+				return doc, nil
+			}
+
+			if len(idxs) != 1 {
+				return nil, NewOpMultipleMatchingIndexErr(currPath, idxs, ptr)
+			}
+
+			idx, err := ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
+			if err != nil {
+				return nil, err
+			}
+
+			if isLast {
+				newAry := reflect.ValueOf([]interface{}{})
+				newAry = reflect.AppendSlice(newAry, ptr.Slice(0, idx))           // not inclusive
+				newAry = reflect.AppendSlice(newAry, ptr.Slice(idx+1, ptr.Len())) // inclusive
+				prevUpdate(newAry.Interface())
+			} else {
+				obj = ptr.Index(idx).Interface()
+				prevUpdate = func(newObj interface{}) {
+					ptr.Index(idx).Set(reflect.ValueOf(newObj))
+				}
+			}
+
 		case KeyToken:
 			ptr := reflect.ValueOf(obj)
 			if ptr.Kind() != reflect.Map {
@@ -89,7 +136,7 @@ func (op RemoveOp) Apply(doc interface{}) (interface{}, error) {
 				if typedToken.Optional {
 					return doc, nil
 				}
-				return nil, OpMissingMapKeyErr{typedToken.Key, currPath, ptr}
+				return nil, NewOpMissingMapKeyErr(typedToken.Key, currPath, ptr)
 			} else {
 				obj = mapValue.Interface()
 			}
@@ -103,7 +150,7 @@ func (op RemoveOp) Apply(doc interface{}) (interface{}, error) {
 			}
 
 		default:
-			return nil, OpUnexpectedTokenErr{token, currPath}
+			return nil, OpUnexpectedTokenErr{Token: token, Path: currPath}
 		}
 	}
 
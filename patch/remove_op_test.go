@@ -84,12 +84,12 @@ var _ = Describe("RemoveOp.Apply", func() {
 			_, err := RemoveOp{Path: MustNewPointerFromString("/1")}.Apply([]interface{}{})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find array index '1' but found array of length '0' for path '/1'"))
+				"Expected to find array index '1' but found array of length '0' for path '/1' (array is empty)"))
 
 			_, err = RemoveOp{Path: MustNewPointerFromString("/1/1")}.Apply([]interface{}{})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find array index '1' but found array of length '0' for path '/1'"))
+				"Expected to find array index '1' but found array of length '0' for path '/1' (array is empty)"))
 		})
 	})
 
@@ -146,7 +146,7 @@ var _ = Describe("RemoveOp.Apply", func() {
 			_, err := RemoveOp{Path: MustNewPointerFromString("/key=val")}.Apply(doc)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find exactly one matching array item for path '/key=val' but found 0"))
+				"Expected to find exactly one matching array item for path '/key=val' but found 0 (did you mean 'key=val2'?)"))
 		})
 
 		It("returns an error if multiple items found", func() {
@@ -256,6 +256,42 @@ var _ = Describe("RemoveOp.Apply", func() {
 		})
 	})
 
+	Describe("array item with matching value", func() {
+		It("removes scalar array item", func() {
+			doc := map[interface{}]interface{}{"azs": []interface{}{"z1", "z2", "z3"}}
+
+			res, err := RemoveOp{Path: MustNewPointerFromString("/azs/=z2")}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"azs": []interface{}{"z1", "z3"}}))
+		})
+
+		It("removes relative scalar array item", func() {
+			res, err := RemoveOp{Path: MustNewPointerFromString("/=z2:prev")}.Apply([]interface{}{"z1", "z2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"z2"}))
+		})
+
+		It("removes nothing if item does not exist and is not expected to exist", func() {
+			res, err := RemoveOp{Path: MustNewPointerFromString("/=z3?")}.Apply([]interface{}{"z1", "z2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"z1", "z2"}))
+		})
+
+		It("returns an error if no items found", func() {
+			_, err := RemoveOp{Path: MustNewPointerFromString("/=z3")}.Apply([]interface{}{"z1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find exactly one matching array item for path '/=z3' but found 0"))
+		})
+
+		It("returns an error if it's not an array is being accessed", func() {
+			_, err := RemoveOp{Path: MustNewPointerFromString("/=z1")}.Apply(map[interface{}]interface{}{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find an array at path '/=z1' but found 'map[interface {}]interface {}'"))
+		})
+	})
+
 	Describe("map key", func() {
 		It("removes map key", func() {
 			doc := map[interface{}]interface{}{
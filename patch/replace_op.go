@@ -1,6 +1,7 @@
 package patch
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -10,9 +11,20 @@ import (
 type ReplaceOp struct {
 	Path  Pointer
 	Value interface{} // will be cloned using yaml library
+
+	// Idempotent skips appending or inserting values that are already present in the array
+	Idempotent bool
 }
 
 func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
+	return op.apply(context.Background(), doc)
+}
+
+func (op ReplaceOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	return op.apply(ctx, doc)
+}
+
+func (op ReplaceOp) apply(ctx context.Context, doc interface{}) (interface{}, error) {
 	// Ensure that value is not modified by future operations
 	clonedValue, err := op.cloneValue(op.Value)
 	if err != nil {
@@ -47,6 +59,10 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 					return nil, err
 				}
 
+				if idx.insert && op.skipsPresent(ptr, clonedValue) {
+					return doc, nil
+				}
+
 				prevUpdate(idx.Update(ptr, clonedValue))
 			} else {
 				idx, err := ArrayIndex{Index: typedToken.Index, Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
@@ -65,9 +81,13 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 			}
 
 			if isLast {
+				if op.skipsPresent(ptr, clonedValue) {
+					return doc, nil
+				}
+
 				prevUpdate(reflect.Append(ptr, reflect.ValueOf(clonedValue)).Interface())
 			} else {
-				return nil, fmt.Errorf("Expected after last index token to be last in path '%s'", op.Path)
+				return nil, OpUnsupportedTokenErr{Token: typedToken, Path: op.Path}
 			}
 
 		case MatchingIndexToken:
@@ -76,10 +96,17 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 				return nil, NewOpArrayMismatchTypeErr(currPath, obj)
 			}
 
-			idxs := findMapIndices(ptr, typedToken.Key, typedToken.Value)
+			idxs, err := findMapIndicesContext(ctx, ptr, typedToken.Key, typedToken.Value)
+			if err != nil {
+				return nil, err
+			}
 
 			if typedToken.Optional && len(idxs) == 0 {
 				if isLast {
+					if op.skipsPresent(ptr, clonedValue) {
+						return doc, nil
+					}
+
 					prevUpdate(reflect.Append(ptr, reflect.ValueOf(clonedValue)).Interface())
 				} else {
 					obj = map[interface{}]interface{}{typedToken.Key: typedToken.Value}
@@ -88,7 +115,7 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 				}
 			} else {
 				if len(idxs) != 1 {
-					return nil, OpMultipleMatchingIndexErr{currPath, idxs}
+					return nil, NewOpMultipleMatchingIndexErr(currPath, idxs, ptr)
 				}
 
 				if isLast && len(idxs) == 1 {
@@ -97,6 +124,10 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 						return nil, err
 					}
 
+					if idx.insert && op.skipsPresent(ptr, clonedValue) {
+						return doc, nil
+					}
+
 					prevUpdate(idx.Update(ptr, clonedValue))
 				} else if len(idxs) == 1 {
 					idx, err := ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
@@ -109,6 +140,51 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 				}
 			}
 
+		case MatchingValueToken:
+			ptr := reflect.ValueOf(obj)
+			if ptr.Kind() != reflect.Slice {
+				return nil, NewOpArrayMismatchTypeErr(currPath, obj)
+			}
+
+			idxs, err := findValueIndicesContext(ctx, ptr, typedToken.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			if typedToken.Optional && len(idxs) == 0 {
+				if !isLast {
+					return nil, OpUnsupportedTokenErr{Token: typedToken, Path: op.Path}
+				}
+
+				// ensures that value is present
+				prevUpdate(reflect.Append(ptr, reflect.ValueOf(clonedValue)).Interface())
+			} else {
+				if len(idxs) != 1 {
+					return nil, NewOpMultipleMatchingIndexErr(currPath, idxs, ptr)
+				}
+
+				if isLast {
+					idx, err := ArrayInsertion{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
+					if err != nil {
+						return nil, err
+					}
+
+					if idx.insert && op.skipsPresent(ptr, clonedValue) {
+						return doc, nil
+					}
+
+					prevUpdate(idx.Update(ptr, clonedValue))
+				} else {
+					idx, err := ArrayIndex{Index: idxs[0], Modifiers: typedToken.Modifiers, Array: ptr, Path: currPath}.Concrete()
+					if err != nil {
+						return nil, err
+					}
+
+					obj = ptr.Index(idx).Interface()
+					prevUpdate = func(newObj interface{}) { ptr.Index(idx).Set(reflect.ValueOf(newObj)) }
+				}
+			}
+
 		case KeyToken:
 			ptr := reflect.ValueOf(obj)
 			if ptr.Kind() != reflect.Map {
@@ -122,7 +198,7 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 				found = true
 			} else {
 				if !typedToken.Optional {
-					return nil, OpMissingMapKeyErr{typedToken.Key, currPath, ptr}
+					return nil, NewOpMissingMapKeyErr(typedToken.Key, currPath, ptr)
 				}
 
 				found = false
@@ -149,13 +225,13 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 					switch tokens[i+2].(type) {
 					case AfterLastIndexToken:
 						obj = []interface{}{}
-					case MatchingIndexToken:
+					case MatchingIndexToken, MatchingValueToken:
 						obj = []interface{}{}
 					case KeyToken:
 						obj = map[interface{}]interface{}{}
 					default:
-						errMsg := "Expected to find key, matching index or after last index token at path '%s'"
-						return nil, fmt.Errorf(errMsg, NewPointer(tokens[:i+3]))
+						path := NewPointer(tokens[:i+3])
+						return nil, OpUnexpectedTokenErr{Token: tokens[i+2], Path: path, Expected: "key, matching index or after last index"}
 					}
 
 					setValue(obj)
@@ -163,13 +239,28 @@ func (op ReplaceOp) Apply(doc interface{}) (interface{}, error) {
 			}
 
 		default:
-			return nil, OpUnexpectedTokenErr{token, currPath}
+			return nil, OpUnexpectedTokenErr{Token: token, Path: currPath}
 		}
 	}
 
 	return doc, nil
 }
 
+// skipsPresent checks whether value should not be added since it's already present
+func (op ReplaceOp) skipsPresent(array reflect.Value, value interface{}) bool {
+	if !op.Idempotent {
+		return false
+	}
+
+	for i := 0; i < array.Len(); i++ {
+		if reflect.DeepEqual(array.Index(i).Interface(), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ReplaceOp) cloneValue(in interface{}) (out interface{}, err error) {
 	defer func() {
 		if recoverVal := recover(); recoverVal != nil {
@@ -110,12 +110,12 @@ var _ = Describe("ReplaceOp.Apply", func() {
 			_, err := ReplaceOp{Path: MustNewPointerFromString("/1")}.Apply([]interface{}{})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find array index '1' but found array of length '0' for path '/1'"))
+				"Expected to find array index '1' but found array of length '0' for path '/1' (array is empty)"))
 
 			_, err = ReplaceOp{Path: MustNewPointerFromString("/1/1")}.Apply([]interface{}{})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find array index '1' but found array of length '0' for path '/1'"))
+				"Expected to find array index '1' but found array of length '0' for path '/1' (array is empty)"))
 		})
 	})
 
@@ -240,7 +240,7 @@ var _ = Describe("ReplaceOp.Apply", func() {
 			_, err := ReplaceOp{Path: MustNewPointerFromString("/key=val")}.Apply(doc)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(
-				"Expected to find exactly one matching array item for path '/key=val' but found 0"))
+				"Expected to find exactly one matching array item for path '/key=val' but found 0 (did you mean 'key=val2'?)"))
 		})
 
 		It("returns an error if multiple items found", func() {
@@ -336,6 +336,72 @@ var _ = Describe("ReplaceOp.Apply", func() {
 		})
 	})
 
+	Describe("array item with matching value", func() {
+		It("replaces scalar array item", func() {
+			doc := map[interface{}]interface{}{"azs": []interface{}{"z1", "z2"}}
+
+			res, err := ReplaceOp{Path: MustNewPointerFromString("/azs/=z2"), Value: "z3"}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"azs": []interface{}{"z1", "z3"}}))
+		})
+
+		It("inserts item before or after scalar array item", func() {
+			res, err := ReplaceOp{Path: MustNewPointerFromString("/=z2:before"), Value: "z3"}.Apply([]interface{}{"z1", "z2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"z1", "z3", "z2"}))
+
+			res, err = ReplaceOp{Path: MustNewPointerFromString("/=z1:after"), Value: "z3"}.Apply([]interface{}{"z1", "z2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"z1", "z3", "z2"}))
+		})
+
+		It("appends missing item only if it does not exist", func() {
+			doc := map[interface{}]interface{}{"azs": []interface{}{"z1", "z2"}}
+
+			res, err := ReplaceOp{Path: MustNewPointerFromString("/azs/=z3?"), Value: "z3"}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"azs": []interface{}{"z1", "z2", "z3"}}))
+
+			res, err = ReplaceOp{Path: MustNewPointerFromString("/azs/=z3?"), Value: "z3"}.Apply(res)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"azs": []interface{}{"z1", "z2", "z3"}}))
+		})
+
+		It("creates missing array if it's not expected to exist", func() {
+			res, err := ReplaceOp{Path: MustNewPointerFromString("/azs?/=z1"), Value: "z1"}.Apply(map[interface{}]interface{}{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"azs": []interface{}{"z1"}}))
+		})
+
+		It("replaces nested array within matching array item", func() {
+			doc := []interface{}{[]interface{}{"a"}, "b"}
+
+			res, err := ReplaceOp{Path: MustNewPointerFromString("/=b:prev/0"), Value: "c"}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{[]interface{}{"c"}, "b"}))
+		})
+
+		It("returns an error if no items found and matching is not optional", func() {
+			_, err := ReplaceOp{Path: MustNewPointerFromString("/=z3"), Value: "z3"}.Apply([]interface{}{"z1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find exactly one matching array item for path '/=z3' but found 0"))
+		})
+
+		It("returns an error if missing item is not last", func() {
+			_, err := ReplaceOp{Path: MustNewPointerFromString("/=z3?/key"), Value: 1}.Apply([]interface{}{"z1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected missing matching value to be last in path '/=z3?/key'"))
+		})
+
+		It("returns an error if it's not an array is being accessed", func() {
+			_, err := ReplaceOp{Path: MustNewPointerFromString("/=z1")}.Apply(map[interface{}]interface{}{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected to find an array at path '/=z1' but found 'map[interface {}]interface {}'"))
+		})
+	})
+
 	Describe("map key", func() {
 		It("replaces map key", func() {
 			doc := map[interface{}]interface{}{
@@ -0,0 +1,598 @@
+package patch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Schema is a JSON Schema (draft 7 subset) used to validate documents:
+//   - $ref (JSON pointers within the same schema), type, enum, const
+//   - allOf, anyOf, oneOf, not, if/then/else
+//   - properties, patternProperties, additionalProperties, required, minProperties, maxProperties
+//   - items, minItems, maxItems, uniqueItems, contains
+//   - minLength, maxLength, pattern
+//   - minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//
+// Other keywords (e.g. format) are ignored.
+type Schema struct {
+	Definition interface{}
+	Source     string // file schema was loaded from if any
+}
+
+// SchemaViolation describes a value that does not match schema
+type SchemaViolation struct {
+	Path    Pointer
+	Message string
+	Index   int // operation that introduced violation (set by ValidatedOps); -1 if unknown
+}
+
+func (v SchemaViolation) String() string {
+	str := fmt.Sprintf("'%s': %s", v.Path, v.Message)
+
+	if v.Index >= 0 {
+		str += fmt.Sprintf(" (introduced by operation [%d])", v.Index)
+	}
+
+	return str
+}
+
+// NewSchemaFromFile loads schema from a JSON or YAML file
+func NewSchemaFromFile(path string) (Schema, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Schema{}, fmt.Errorf("Reading schema '%s': %s", path, err)
+	}
+
+	var def interface{}
+
+	err = yaml.Unmarshal(bytes, &def)
+	if err != nil {
+		return Schema{}, fmt.Errorf("Deserializing schema '%s': %s", path, err)
+	}
+
+	return Schema{Definition: def, Source: path}, nil
+}
+
+// SchemaLoader loads schema referenced by validate operation
+type SchemaLoader func(path string) (Schema, error)
+
+// NewSchemaDirLoader loads schemas relative to dir (ex: directory of ops file);
+// absolute paths and paths outside of dir are rejected
+func NewSchemaDirLoader(dir string) SchemaLoader {
+	dir = filepath.Clean(dir)
+
+	return func(path string) (Schema, error) {
+		if filepath.IsAbs(path) {
+			return Schema{}, fmt.Errorf("Expected schema path '%s' to be relative", path)
+		}
+
+		fullPath := filepath.Join(dir, path)
+
+		relPath, err := filepath.Rel(dir, fullPath)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return Schema{}, fmt.Errorf("Expected schema path '%s' to be within '%s'", path, dir)
+		}
+
+		schema, err := NewSchemaFromFile(fullPath)
+		if err != nil {
+			return Schema{}, err
+		}
+
+		// keeps path as referenced for serialization
+		schema.Source = path
+
+		return schema, nil
+	}
+}
+
+// Validate returns violations with paths relative to the value
+func (s Schema) Validate(val interface{}) []SchemaViolation {
+	v := schemaValidator{root: s.Definition}
+	return v.validate(s.Definition, val, []Token{RootToken{}})
+}
+
+type schemaValidator struct {
+	root interface{}
+
+	refs      []string // references followed without descending into value
+	refsDepth int
+}
+
+func (v schemaValidator) validate(schema, val interface{}, tokens []Token) []SchemaViolation {
+	switch typedSchema := schema.(type) {
+	case bool:
+		if !typedSchema {
+			return v.violation(tokens, "Expected no value to be allowed by schema 'false'")
+		}
+		return nil
+
+	case map[interface{}]interface{}:
+		if ref, ok := typedSchema["$ref"].(string); ok {
+			if v.refsDepth != len(tokens) {
+				v.refs, v.refsDepth = nil, len(tokens)
+			}
+
+			for _, visitedRef := range v.refs {
+				if visitedRef == ref {
+					return v.violation(tokens, fmt.Sprintf("Expected schema reference '%s' to not be circular", ref))
+				}
+			}
+
+			refSchema, err := v.resolve(ref)
+			if err != nil {
+				return v.violation(tokens, err.Error())
+			}
+
+			v.refs = append(v.refs[:len(v.refs):len(v.refs)], ref)
+
+			// sibling keywords are ignored next to $ref (as of draft 7)
+			return v.validate(refSchema, val, tokens)
+		}
+
+		var violations []SchemaViolation
+
+		for _, check := range []func(map[interface{}]interface{}, interface{}, []Token) []SchemaViolation{
+			v.validateType, v.validateValues, v.validateCombinations,
+			v.validateObject, v.validateArray, v.validateString, v.validateNumber,
+		} {
+			violations = append(violations, check(typedSchema, val, tokens)...)
+		}
+
+		return violations
+
+	case nil:
+		return nil
+
+	default:
+		return v.violation(tokens, fmt.Sprintf("Expected schema to be an object or a boolean but found '%T'", schema))
+	}
+}
+
+func (v schemaValidator) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("Expected schema reference '%s' to be local (starting with '#')", ref)
+	}
+
+	// fragment is a JSON Pointer (RFC 6901) rather than a go-patch path
+	ptr := strings.TrimPrefix(ref, "#")
+	if len(ptr) == 0 {
+		return v.root, nil
+	}
+
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("Expected schema reference '%s' to start with '#/'", ref)
+	}
+
+	refSchema := v.root
+
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		var found bool
+
+		switch typedSchema := refSchema.(type) {
+		case map[interface{}]interface{}:
+			refSchema, found = typedSchema[token]
+			if !found {
+				// keys such as 0 or true are not decoded as strings
+				for k, val := range typedSchema {
+					if fmt.Sprintf("%v", k) == token {
+						refSchema, found = val, true
+						break
+					}
+				}
+			}
+
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err == nil && idx >= 0 && idx < len(typedSchema) && token == strconv.Itoa(idx) {
+				refSchema, found = typedSchema[idx], true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Expected to find schema reference '%s' but found no value for '%s'", ref, token)
+		}
+	}
+
+	return refSchema, nil
+}
+
+func (v schemaValidator) validateType(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	var types []string
+
+	switch typedType := schema["type"].(type) {
+	case string:
+		types = []string{typedType}
+	case []interface{}:
+		for _, t := range typedType {
+			types = append(types, fmt.Sprintf("%v", t))
+		}
+	default:
+		return nil
+	}
+
+	actual := v.typeOf(val)
+
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return nil
+		}
+	}
+
+	return v.violation(tokens, fmt.Sprintf("Expected value of type '%s' but found '%s'", strings.Join(types, "', '"), actual))
+}
+
+func (v schemaValidator) validateValues(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	var violations []SchemaViolation
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+
+		for _, item := range enum {
+			if v.equal(item, val) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			violations = append(violations, v.violation(tokens,
+				fmt.Sprintf("Expected value to be one of %s but found %s", v.format(tokens, enum), v.format(tokens, val)))...)
+		}
+	}
+
+	if constVal, ok := schema["const"]; ok && !v.equal(constVal, val) {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected value to equal %s but found %s", v.format(tokens, constVal), v.format(tokens, val)))...)
+	}
+
+	return violations
+}
+
+func (v schemaValidator) validateCombinations(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	var violations []SchemaViolation
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, subSchema := range allOf {
+			violations = append(violations, v.validate(subSchema, val, tokens)...)
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && v.matching(anyOf, val, tokens) == 0 {
+		violations = append(violations, v.violation(tokens, "Expected value to match at least one schema of 'anyOf'")...)
+	}
+
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		if matching := v.matching(oneOf, val, tokens); matching != 1 {
+			violations = append(violations, v.violation(tokens,
+				fmt.Sprintf("Expected value to match exactly one schema of 'oneOf' but matched %d", matching))...)
+		}
+	}
+
+	if not, ok := schema["not"]; ok && len(v.validate(not, val, tokens)) == 0 {
+		violations = append(violations, v.violation(tokens, "Expected value to not match schema of 'not'")...)
+	}
+
+	if ifSchema, ok := schema["if"]; ok {
+		if len(v.validate(ifSchema, val, tokens)) == 0 {
+			violations = append(violations, v.validate(schema["then"], val, tokens)...)
+		} else {
+			violations = append(violations, v.validate(schema["else"], val, tokens)...)
+		}
+	}
+
+	return violations
+}
+
+func (v schemaValidator) validateObject(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	obj, ok := val.(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+
+	var violations []SchemaViolation
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			if _, found := obj[key]; !found {
+				violations = append(violations, v.violation(tokens, fmt.Sprintf("Expected to find required key '%v'", key))...)
+			}
+		}
+	}
+
+	if min, ok := v.number(schema["minProperties"]); ok && float64(len(obj)) < min {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected to find at least %v keys but found %d", min, len(obj)))...)
+	}
+
+	if max, ok := v.number(schema["maxProperties"]); ok && float64(len(obj)) > max {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected to find at most %v keys but found %d", max, len(obj)))...)
+	}
+
+	properties, _ := schema["properties"].(map[interface{}]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[interface{}]interface{})
+	additionalProperties, hasAdditional := schema["additionalProperties"]
+
+	for _, key := range v.sortedKeys(obj) {
+		keyStr := fmt.Sprintf("%v", key)
+		keyTokens := append(append([]Token{}, tokens...), KeyToken{Key: keyStr})
+
+		matched := false
+
+		if propSchema, found := properties[key]; found {
+			violations = append(violations, v.validate(propSchema, obj[key], keyTokens)...)
+			matched = true
+		}
+
+		for pattern, propSchema := range patternProperties {
+			re, err := regexp.Compile(fmt.Sprintf("%v", pattern))
+			if err != nil {
+				violations = append(violations, v.violation(tokens, fmt.Sprintf("Expected valid pattern '%v': %s", pattern, err))...)
+				continue
+			}
+
+			if re.MatchString(keyStr) {
+				violations = append(violations, v.validate(propSchema, obj[key], keyTokens)...)
+				matched = true
+			}
+		}
+
+		if !matched && hasAdditional {
+			if allowed, ok := additionalProperties.(bool); ok && !allowed {
+				violations = append(violations, v.violation(keyTokens, fmt.Sprintf("Expected to not find additional key '%s'", keyStr))...)
+			} else {
+				violations = append(violations, v.validate(additionalProperties, obj[key], keyTokens)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func (v schemaValidator) validateArray(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	items, ok := val.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var violations []SchemaViolation
+
+	if min, ok := v.number(schema["minItems"]); ok && float64(len(items)) < min {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected to find at least %v items but found %d", min, len(items)))...)
+	}
+
+	if max, ok := v.number(schema["maxItems"]); ok && float64(len(items)) > max {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected to find at most %v items but found %d", max, len(items)))...)
+	}
+
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if v.equal(items[i], items[j]) {
+					violations = append(violations, v.violation(v.indexTokens(tokens, i),
+						fmt.Sprintf("Expected items to be unique but item is equal to item [%d]", j))...)
+					break
+				}
+			}
+		}
+	}
+
+	for i, item := range items {
+		switch itemsSchema := schema["items"].(type) {
+		case []interface{}:
+			if i < len(itemsSchema) {
+				violations = append(violations, v.validate(itemsSchema[i], item, v.indexTokens(tokens, i))...)
+			}
+		case nil:
+		default:
+			violations = append(violations, v.validate(itemsSchema, item, v.indexTokens(tokens, i))...)
+		}
+	}
+
+	if contains, ok := schema["contains"]; ok {
+		found := false
+
+		for i, item := range items {
+			if len(v.validate(contains, item, v.indexTokens(tokens, i))) == 0 {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			violations = append(violations, v.violation(tokens, "Expected to find an item matching schema of 'contains'")...)
+		}
+	}
+
+	return violations
+}
+
+func (v schemaValidator) validateString(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	str, ok := val.(string)
+	if !ok {
+		return nil
+	}
+
+	var violations []SchemaViolation
+
+	length := utf8.RuneCountInString(str)
+
+	if min, ok := v.number(schema["minLength"]); ok && float64(length) < min {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected string of at least %v characters but found %d", min, length))...)
+	}
+
+	if max, ok := v.number(schema["maxLength"]); ok && float64(length) > max {
+		violations = append(violations, v.violation(tokens,
+			fmt.Sprintf("Expected string of at most %v characters but found %d", max, length))...)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			violations = append(violations, v.violation(tokens, fmt.Sprintf("Expected valid pattern '%s': %s", pattern, err))...)
+		} else if !re.MatchString(str) {
+			violations = append(violations, v.violation(tokens,
+				fmt.Sprintf("Expected string to match pattern '%s' but found %s", pattern, v.format(tokens, str)))...)
+		}
+	}
+
+	return violations
+}
+
+func (v schemaValidator) validateNumber(schema map[interface{}]interface{}, val interface{}, tokens []Token) []SchemaViolation {
+	num, ok := v.number(val)
+	if !ok {
+		return nil
+	}
+
+	var violations []SchemaViolation
+
+	checks := []struct {
+		keyword string
+		fails   func(float64) bool
+		desc    string
+	}{
+		{"minimum", func(limit float64) bool { return num < limit }, "greater than or equal to"},
+		{"maximum", func(limit float64) bool { return num > limit }, "less than or equal to"},
+		{"exclusiveMinimum", func(limit float64) bool { return num <= limit }, "greater than"},
+		{"exclusiveMaximum", func(limit float64) bool { return num >= limit }, "less than"},
+		{"multipleOf", func(limit float64) bool { return limit != 0 && math.Mod(num, limit) != 0 }, "a multiple of"},
+	}
+
+	for _, check := range checks {
+		if limit, ok := v.number(schema[check.keyword]); ok && check.fails(limit) {
+			violations = append(violations, v.violation(tokens,
+				fmt.Sprintf("Expected number %s %v but found %v", check.desc, limit, num))...)
+		}
+	}
+
+	return violations
+}
+
+func (v schemaValidator) matching(schemas []interface{}, val interface{}, tokens []Token) int {
+	var matching int
+
+	for _, subSchema := range schemas {
+		if len(v.validate(subSchema, val, tokens)) == 0 {
+			matching++
+		}
+	}
+
+	return matching
+}
+
+func (schemaValidator) typeOf(val interface{}) string {
+	switch typedVal := val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[interface{}]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float32, float64:
+		if f := reflect.ValueOf(typedVal).Float(); f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	default:
+		return fmt.Sprintf("%T", val)
+	}
+}
+
+// number converts numeric values since YAML library decodes numbers into different types
+func (schemaValidator) number(val interface{}) (float64, bool) {
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func (v schemaValidator) equal(left, right interface{}) bool {
+	if leftNum, ok := v.number(left); ok {
+		rightNum, ok := v.number(right)
+		return ok && leftNum == rightNum
+	}
+
+	switch typedLeft := left.(type) {
+	case []interface{}:
+		typedRight, ok := right.([]interface{})
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false
+		}
+		for i := range typedLeft {
+			if !v.equal(typedLeft[i], typedRight[i]) {
+				return false
+			}
+		}
+		return true
+
+	case map[interface{}]interface{}:
+		typedRight, ok := right.(map[interface{}]interface{})
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false
+		}
+		for k, leftVal := range typedLeft {
+			rightVal, found := typedRight[k]
+			if !found || !v.equal(leftVal, rightVal) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return reflect.DeepEqual(left, right)
+	}
+}
+
+func (schemaValidator) format(tokens []Token, val interface{}) string {
+	return DefaultRedactionPolicy().fmtValue(NewPointer(tokens), val)
+}
+
+func (schemaValidator) sortedKeys(obj map[interface{}]interface{}) []interface{} {
+	var keys []interface{}
+
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool { return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j]) })
+
+	return keys
+}
+
+func (schemaValidator) indexTokens(tokens []Token, i int) []Token {
+	return append(append([]Token{}, tokens...), IndexToken{Index: i})
+}
+
+func (schemaValidator) violation(tokens []Token, msg string) []SchemaViolation {
+	return []SchemaViolation{{Path: NewPointer(tokens), Message: msg, Index: -1}}
+}
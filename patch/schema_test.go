@@ -0,0 +1,179 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("Schema.Validate", func() {
+	parse := func(str string) interface{} {
+		var val interface{}
+		Expect(yaml.Unmarshal([]byte(str), &val)).To(Succeed())
+		return val
+	}
+
+	validate := func(schema, doc string) []string {
+		var msgs []string
+		for _, violation := range (Schema{Definition: parse(schema)}).Validate(parse(doc)) {
+			msgs = append(msgs, violation.String())
+		}
+		return msgs
+	}
+
+	It("returns no violations for matching values", func() {
+		schema := `
+type: object
+required: [name, instance_groups]
+properties:
+  name: {type: string, pattern: "^[a-z-]+$"}
+  instance_groups:
+    type: array
+    items: {$ref: "#/definitions/ig"}
+definitions:
+  ig:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string, minLength: 1}
+      instances: {type: integer, minimum: 0}
+      azs: {type: array, items: {enum: [z1, z2]}, uniqueItems: true}
+    additionalProperties: false
+`
+		Expect(validate(schema, `
+name: dep
+instance_groups:
+- name: api
+  instances: 2.0
+  azs: [z1, z2]
+`)).To(BeEmpty())
+	})
+
+	It("reports violations with paths of failing values", func() {
+		schema := `
+type: object
+required: [name, instance_groups]
+properties:
+  name: {type: string, pattern: "^[a-z-]+$"}
+  instance_groups:
+    type: array
+    items: {$ref: "#/definitions/ig"}
+definitions:
+  ig:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string, minLength: 1}
+      instances: {type: integer, minimum: 0}
+      azs: {type: array, items: {enum: [z1, z2]}, uniqueItems: true}
+    additionalProperties: false
+`
+		Expect(validate(schema, `
+name: Dep
+instance_groups:
+- instances: -1
+  azs: [z1, z3, z1]
+  extra: true
+- name: ""
+  instances: 1.5
+`)).To(Equal([]string{
+			"'/instance_groups/0': Expected to find required key 'name'",
+			"'/instance_groups/0/azs/2': Expected items to be unique but item is equal to item [0]",
+			"'/instance_groups/0/azs/1': Expected value to be one of [\"z1\",\"z2\"] but found \"z3\"",
+			"'/instance_groups/0/extra': Expected to not find additional key 'extra'",
+			"'/instance_groups/0/instances': Expected number greater than or equal to 0 but found -1",
+			"'/instance_groups/1/instances': Expected value of type 'integer' but found 'number'",
+			"'/instance_groups/1/name': Expected string of at least 1 characters but found 0",
+			"'/name': Expected string to match pattern '^[a-z-]+$' but found \"Dep\"",
+		}))
+	})
+
+	It("supports combinations of schemas", func() {
+		schema := `
+anyOf: [{type: string}, {type: integer}]
+oneOf: [{maximum: 3}, {minimum: 5}]
+not: {const: 5}
+`
+		Expect(validate(schema, `3`)).To(BeEmpty())
+		Expect(validate(schema, `true`)).To(Equal([]string{
+			"'': Expected value to match at least one schema of 'anyOf'",
+			"'': Expected value to match exactly one schema of 'oneOf' but matched 2",
+		}))
+		Expect(validate(schema, `4`)).To(Equal([]string{"'': Expected value to match exactly one schema of 'oneOf' but matched 0"}))
+		Expect(validate(schema, `5`)).To(Equal([]string{"'': Expected value to not match schema of 'not'"}))
+
+		schema = `
+if: {properties: {kind: {const: vm}}}
+then: {required: [size]}
+else: {required: [image]}
+`
+		Expect(validate(schema, `{kind: vm, size: 1}`)).To(BeEmpty())
+		Expect(validate(schema, `{kind: vm}`)).To(Equal([]string{"'': Expected to find required key 'size'"}))
+		Expect(validate(schema, `{kind: container}`)).To(Equal([]string{"'': Expected to find required key 'image'"}))
+	})
+
+	It("supports array and object sizes", func() {
+		schema := `
+type: [array, object]
+minItems: 1
+maxItems: 2
+contains: {type: string}
+maxProperties: 1
+additionalProperties: {type: integer}
+patternProperties:
+  "^x-": {type: string}
+`
+		Expect(validate(schema, `[1, a]`)).To(BeEmpty())
+		Expect(validate(schema, `[1, 2, 3]`)).To(Equal([]string{
+			"'': Expected to find at most 2 items but found 3",
+			"'': Expected to find an item matching schema of 'contains'",
+		}))
+		Expect(validate(schema, `{x-a: 1, b: c}`)).To(Equal([]string{
+			"'': Expected to find at most 1 keys but found 2",
+			"'/b': Expected value of type 'integer' but found 'string'",
+			"'/x-a': Expected value of type 'string' but found 'integer'",
+		}))
+		Expect(validate(schema, `a`)).To(Equal([]string{"'': Expected value of type 'array', 'object' but found 'string'"}))
+	})
+
+	It("resolves references as JSON pointers", func() {
+		schema := `
+definitions:
+  0: {type: string}
+  a/b: {type: integer}
+  k=v: {type: boolean}
+  list: [{type: "null"}]
+properties:
+  zero: {$ref: "#/definitions/0"}
+  slash: {$ref: "#/definitions/a~1b"}
+  equal: {$ref: "#/definitions/k=v"}
+  item: {$ref: "#/definitions/list/0"}
+  tree:
+    properties:
+      children: {type: array, items: {$ref: "#/properties/tree"}}
+`
+		Expect(validate(schema, `{zero: a, slash: 1, equal: true, item: null, tree: {children: [{children: []}]}}`)).To(BeEmpty())
+		Expect(validate(schema, `{zero: 1, slash: a, equal: 1, item: 1, tree: {children: [{children: 1}]}}`)).To(Equal([]string{
+			"'/equal': Expected value of type 'boolean' but found 'integer'",
+			"'/item': Expected value of type 'null' but found 'integer'",
+			"'/slash': Expected value of type 'integer' but found 'string'",
+			"'/tree/children/0/children': Expected value of type 'array' but found 'integer'",
+			"'/zero': Expected value of type 'string' but found 'integer'",
+		}))
+	})
+
+	It("reports invalid schemas as violations", func() {
+		Expect(validate(`{$ref: "#/missing"}`, `1`)).To(Equal([]string{
+			"'': Expected to find schema reference '#/missing' but found no value for 'missing'",
+		}))
+		Expect(validate(`{definitions: {a: {$ref: "#/definitions/b"}, b: {allOf: [{$ref: "#/definitions/a"}]}}, $ref: "#/definitions/a"}`, `1`)).To(Equal([]string{
+			"'': Expected schema reference '#/definitions/a' to not be circular",
+		}))
+		Expect(validate(`{$ref: "other.json"}`, `1`)).To(Equal([]string{
+			"'': Expected schema reference 'other.json' to be local (starting with '#')",
+		}))
+		Expect(validate(`false`, `1`)).To(Equal([]string{"'': Expected no value to be allowed by schema 'false'"}))
+	})
+})
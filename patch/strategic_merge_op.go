@@ -0,0 +1,505 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	strategicMergeDirective        = "$patch"
+	strategicMergeDirectiveDelete  = "delete"
+	strategicMergeDirectiveReplace = "replace"
+	strategicMergeDirectiveMerge   = "merge"
+)
+
+// StrategicMergeOp applies a strategic merge patch (similar to Kubernetes)
+// to the document at a path:
+//   - maps are merged recursively and null values remove keys
+//   - lists of maps are merged by a merge key ('name' by default);
+//     other lists are replaced
+//   - '$patch: delete' within a map value or list item removes it
+//   - '$patch: replace' within a map replaces it instead of merging;
+//     a list item consisting of '$patch: replace' replaces the list
+type StrategicMergeOp struct {
+	Path  Pointer
+	Value interface{} // will be cloned using yaml library
+
+	// MergeKeys overrides merge keys for lists at specific paths
+	// (e.g. '/instance_groups/*/networks' => 'name'); '*' matches any token.
+	// Empty merge key results in list replacement.
+	MergeKeys map[string]string
+}
+
+func (op StrategicMergeOp) Apply(doc interface{}) (interface{}, error) {
+	return op.apply(context.Background(), doc)
+}
+
+func (op StrategicMergeOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	return op.apply(ctx, doc)
+}
+
+func (op StrategicMergeOp) apply(ctx context.Context, doc interface{}) (interface{}, error) {
+	// Ensure that value is not modified by future operations
+	clonedValue, err := ReplaceOp{}.cloneValue(op.Value)
+	if err != nil {
+		return nil, fmt.Errorf("StrategicMergeOp cloning value: %s", err)
+	}
+
+	target, err := FindOp{Path: op.Path}.ApplyContext(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, keep, err := newStrategicMerge(op.MergeKeys).merge(target, clonedValue, op.Path.Tokens())
+	if err != nil {
+		return nil, err
+	}
+
+	if !keep {
+		return RemoveOp{Path: op.Path}.ApplyContext(ctx, doc)
+	}
+
+	return ReplaceOp{Path: op.Path, Value: merged}.ApplyContext(ctx, doc)
+}
+
+// CalculateStrategicMergePatch returns a strategic merge patch that converts
+// left document into right document. Keyed lists are replaced when their
+// items were reordered since merging appends new items. Similar to RFC 7396,
+// null values cannot be set as they remove keys. Patch of equal documents
+// is an empty map (or left document itself if it is not a map).
+// Ignore and Include patterns select map keys and keyed list items (ex: '/jobs/name=api');
+// other values are replaced as a whole. Unchecked does not apply since there are no tests.
+func (d Diff) CalculateStrategicMergePatch(mergeKeys map[string]string) interface{} {
+	scope, skip := d.filter(d.rootScope(&[]Pointer{}), diffNode{RootToken{}, d.Left, d.Right, true, true})
+	if !skip {
+		if patch, changed := newStrategicMerge(mergeKeys).calculate(d, d.Left, d.Right, scope); changed {
+			return patch
+		}
+	}
+
+	// keep left document as is
+	if _, ok := d.Left.(map[interface{}]interface{}); ok {
+		return map[interface{}]interface{}{}
+	}
+	return d.Left
+}
+
+type strategicMerge struct {
+	mergeKeys map[string]string // keyed by paths without optionality
+}
+
+func newStrategicMerge(mergeKeys map[string]string) strategicMerge {
+	m := strategicMerge{map[string]string{}}
+
+	for path, key := range mergeKeys {
+		if ptr, err := NewPointerFromString(path); err == nil {
+			path = m.path(ptr.Tokens())
+		}
+		m.mergeKeys[path] = key
+	}
+
+	return m
+}
+
+// path returns pointer string ignoring optionality so that merge keys apply to optional paths
+// (ex: '/instance_groups?/jobs' is '/instance_groups/jobs')
+func (strategicMerge) path(tokens []Token) string {
+	segs := analyzer{}.segments(NewPointer(tokens))
+	if len(segs) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+// merge returns merged value and whether value should be kept
+func (m strategicMerge) merge(target, patch interface{}, tokens []Token) (interface{}, bool, error) {
+	switch typedPatch := patch.(type) {
+	case map[interface{}]interface{}:
+		directive, err := m.directive(typedPatch, NewPointer(tokens))
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch directive {
+		case strategicMergeDirectiveDelete:
+			return nil, false, nil
+		case strategicMergeDirectiveReplace:
+			target = nil
+		}
+
+		result := map[interface{}]interface{}{}
+
+		if typedTarget, ok := target.(map[interface{}]interface{}); ok {
+			for k, v := range typedTarget {
+				result[k] = v
+			}
+		}
+
+		for k, v := range typedPatch {
+			if k == strategicMergeDirective {
+				continue
+			}
+
+			if v == nil {
+				delete(result, k)
+				continue
+			}
+
+			newTokens := append(append([]Token{}, tokens...), KeyToken{Key: fmt.Sprintf("%v", k)})
+
+			newV, keep, err := m.merge(result[k], v, newTokens)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if keep {
+				result[k] = newV
+			} else {
+				delete(result, k)
+			}
+		}
+
+		return result, true, nil
+
+	case []interface{}:
+		result, err := m.mergeList(target, typedPatch, tokens)
+		return result, true, err
+
+	default:
+		return patch, true, nil
+	}
+}
+
+func (m strategicMerge) mergeList(target interface{}, patch []interface{}, tokens []Token) (interface{}, error) {
+	var items []interface{}
+
+	replace := false
+
+	for _, item := range patch {
+		if typedItem, ok := item.(map[interface{}]interface{}); ok && len(typedItem) == 1 {
+			directive, err := m.directive(typedItem, NewPointer(tokens))
+			if err != nil {
+				return nil, err
+			}
+			if directive == strategicMergeDirectiveReplace {
+				replace = true
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	key := m.mergeKey(tokens)
+
+	typedTarget, ok := target.([]interface{})
+	if replace || !ok || len(key) == 0 || !m.allKeyed(items, key) {
+		return m.stripDirectives(items), nil
+	}
+
+	result := append([]interface{}{}, typedTarget...)
+
+	for _, item := range items {
+		typedItem := item.(map[interface{}]interface{})
+		keyVal := typedItem[key]
+		newTokens := append(append([]Token{}, tokens...), MatchingIndexToken{Key: key, Value: fmt.Sprintf("%v", keyVal)})
+
+		idx := m.indexOfKey(result, key, keyVal)
+
+		if idx == -1 {
+			newItem, keep, err := m.merge(nil, item, newTokens)
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				result = append(result, newItem)
+			}
+			continue
+		}
+
+		newItem, keep, err := m.merge(result[idx], item, newTokens)
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			result[idx] = newItem
+		} else {
+			result = append(result[:idx], result[idx+1:]...)
+		}
+	}
+
+	return result, nil
+}
+
+func (strategicMerge) directive(patch map[interface{}]interface{}, path Pointer) (string, error) {
+	val, found := patch[strategicMergeDirective]
+	if !found {
+		return strategicMergeDirectiveMerge, nil
+	}
+
+	switch val {
+	case strategicMergeDirectiveDelete, strategicMergeDirectiveReplace, strategicMergeDirectiveMerge:
+		return val.(string), nil
+	default:
+		errMsg := "Expected to find one of the following directives: '%s', '%s' or '%s' but found '%v' for path '%s'"
+		return "", fmt.Errorf(errMsg, strategicMergeDirectiveDelete,
+			strategicMergeDirectiveReplace, strategicMergeDirectiveMerge, val, path)
+	}
+}
+
+func (m strategicMerge) stripDirectives(obj interface{}) interface{} {
+	switch typedObj := obj.(type) {
+	case map[interface{}]interface{}:
+		result := map[interface{}]interface{}{}
+		for k, v := range typedObj {
+			if k != strategicMergeDirective {
+				result[k] = m.stripDirectives(v)
+			}
+		}
+		return result
+
+	case []interface{}:
+		result := []interface{}{}
+		for _, item := range typedObj {
+			result = append(result, m.stripDirectives(item))
+		}
+		return result
+
+	default:
+		return obj
+	}
+}
+
+func (m strategicMerge) mergeKey(tokens []Token) string {
+	path := m.path(tokens)
+
+	if key, found := m.mergeKeys[path]; found {
+		return key
+	}
+
+	// Prefer most specific pattern to make matching deterministic
+	var patterns []string
+
+	for pattern := range m.mergeKeys {
+		if m.matches(pattern, path) {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	if len(patterns) == 0 {
+		return defaultMergeKey
+	}
+
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return strings.Count(patterns[i], "*") < strings.Count(patterns[j], "*")
+	})
+
+	return m.mergeKeys[patterns[0]]
+}
+
+func (strategicMerge) matches(pattern, path string) bool {
+	patternPieces := strings.Split(pattern, "/")
+	pathPieces := strings.Split(path, "/")
+
+	if len(patternPieces) != len(pathPieces) {
+		return false
+	}
+
+	for i, piece := range patternPieces {
+		if piece != "*" && piece != pathPieces[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (strategicMerge) allKeyed(items []interface{}, key string) bool {
+	for _, item := range items {
+		typedItem, ok := item.(map[interface{}]interface{})
+		if !ok {
+			return false
+		}
+		if _, found := typedItem[key]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (strategicMerge) indexOfKey(items []interface{}, key string, keyVal interface{}) int {
+	for i, item := range items {
+		if typedItem, ok := item.(map[interface{}]interface{}); ok {
+			if itemKeyVal, found := typedItem[key]; found && reflect.DeepEqual(itemKeyVal, keyVal) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// calculate returns a patch converting left into right and whether there are any changes
+func (m strategicMerge) calculate(d Diff, left, right interface{}, scope diffScope) (interface{}, bool) {
+	if reflect.DeepEqual(left, right) {
+		return nil, false
+	}
+
+	typedLeft, leftIsMap := left.(map[interface{}]interface{})
+	typedRight, rightIsMap := right.(map[interface{}]interface{})
+
+	if leftIsMap && rightIsMap {
+		patch := map[interface{}]interface{}{}
+
+		for _, k := range m.allKeys(typedLeft, typedRight) {
+			leftVal, leftFound := typedLeft[k]
+			rightVal, rightFound := typedRight[k]
+
+			childScope, skip := d.child(scope, diffNode{KeyToken{Key: fmt.Sprintf("%v", k)}, leftVal, rightVal, leftFound, rightFound})
+			if skip {
+				continue
+			}
+
+			switch {
+			case childScope.included && !rightFound:
+				patch[k] = nil
+			case childScope.included && !leftFound:
+				patch[k] = rightVal
+			default:
+				// look for included keys within value found on one side only
+				if !leftFound {
+					leftVal = emptyLike(rightVal)
+				}
+				if !rightFound {
+					rightVal = emptyLike(leftVal)
+				}
+				if subPatch, changed := m.calculate(d, leftVal, rightVal, childScope); changed {
+					patch[k] = subPatch
+				}
+			}
+		}
+
+		return patch, scope.included || len(patch) > 0
+	}
+
+	leftList, leftIsList := left.([]interface{})
+	rightList, rightIsList := right.([]interface{})
+
+	if leftIsList && rightIsList {
+		key := m.mergeKey(scope.tokens)
+
+		if len(key) > 0 && m.allKeyed(leftList, key) && m.allKeyed(rightList, key) && m.sameKeyOrder(leftList, rightList, key) {
+			patch := []interface{}{}
+
+			for _, rightItem := range rightList {
+				keyVal := rightItem.(map[interface{}]interface{})[key]
+				token := MatchingIndexToken{Key: key, Value: fmt.Sprintf("%v", keyVal)}
+
+				var leftItem interface{}
+				idx := m.indexOfKey(leftList, key, keyVal)
+				if idx != -1 {
+					leftItem = leftList[idx]
+				}
+
+				itemScope, skip := d.child(scope, diffNode{token, leftItem, rightItem, idx != -1, true})
+				if skip {
+					continue
+				}
+
+				if idx == -1 {
+					if itemScope.included {
+						patch = append(patch, rightItem)
+						continue
+					}
+					leftItem = map[interface{}]interface{}{key: keyVal}
+				}
+
+				if subPatch, changed := m.calculate(d, leftItem, rightItem, itemScope); changed {
+					typedSubPatch := subPatch.(map[interface{}]interface{})
+					typedSubPatch[key] = keyVal
+					patch = append(patch, typedSubPatch)
+				}
+			}
+
+			for _, leftItem := range leftList {
+				keyVal := leftItem.(map[interface{}]interface{})[key]
+				if m.indexOfKey(rightList, key, keyVal) != -1 {
+					continue
+				}
+
+				token := MatchingIndexToken{Key: key, Value: fmt.Sprintf("%v", keyVal)}
+
+				itemScope, skip := d.child(scope, diffNode{token, leftItem, nil, true, false})
+				if skip {
+					continue
+				}
+
+				if itemScope.included {
+					patch = append(patch, map[interface{}]interface{}{key: keyVal, strategicMergeDirective: strategicMergeDirectiveDelete})
+					continue
+				}
+
+				// remove included keys of removed item
+				if subPatch, changed := m.calculate(d, leftItem, map[interface{}]interface{}{key: keyVal}, itemScope); changed {
+					typedSubPatch := subPatch.(map[interface{}]interface{})
+					typedSubPatch[key] = keyVal
+					patch = append(patch, typedSubPatch)
+				}
+			}
+
+			return patch, scope.included || len(patch) > 0
+		}
+
+		if len(key) > 0 && m.allKeyed(rightList, key) {
+			// Ensure that items do not get merged into previous list
+			return append([]interface{}{map[interface{}]interface{}{strategicMergeDirective: strategicMergeDirectiveReplace}}, rightList...), scope.included
+		}
+	}
+
+	return right, scope.included
+}
+
+// sameKeyOrder checks that items present on both sides keep their relative
+// order and that added items come after existing ones, since merging appends
+func (strategicMerge) sameKeyOrder(left, right []interface{}, key string) bool {
+	leftIdx := map[interface{}]int{}
+	for i, item := range left {
+		leftIdx[fmt.Sprintf("%v", item.(map[interface{}]interface{})[key])] = i
+	}
+
+	lastIdx := -1
+	seenNew := false
+
+	for _, item := range right {
+		idx, found := leftIdx[fmt.Sprintf("%v", item.(map[interface{}]interface{})[key])]
+		if !found {
+			seenNew = true
+			continue
+		}
+		if seenNew || idx < lastIdx {
+			return false
+		}
+		lastIdx = idx
+	}
+
+	return true
+}
+
+func (strategicMerge) allKeys(left, right map[interface{}]interface{}) []interface{} {
+	var keys []interface{}
+	for k := range left {
+		keys = append(keys, k)
+	}
+	for k := range right {
+		if _, found := left[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,405 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("StrategicMergeOp.Apply", func() {
+	parse := func(str string) interface{} {
+		var obj interface{}
+		Expect(yaml.Unmarshal([]byte(str), &obj)).To(Succeed())
+		return obj
+	}
+
+	var doc interface{}
+
+	BeforeEach(func() {
+		doc = parse(`
+releases:
+- {name: capi, version: 1}
+- {name: uaa, version: 2}
+instance_groups:
+- name: api
+  azs: [z1, z2]
+  networks:
+  - {name: default, static_ips: [10.0.0.1]}
+  jobs:
+  - name: cc
+    release: capi
+    properties: {a: 1, b: 2}
+  - name: nginx
+    release: capi
+- name: uaa
+  instances: 1
+`)
+	})
+
+	It("merges lists by name, maps recursively and replaces lists of scalars", func() {
+		res, err := StrategicMergeOp{
+			Path: MustNewPointerFromString(""),
+			Value: parse(`
+releases:
+- {name: uaa, version: 3}
+- {name: bpm, version: 1}
+instance_groups:
+- name: api
+  azs: [z3]
+  jobs:
+  - name: cc
+    properties: {b: null, c: 3}
+`),
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`
+releases:
+- {name: capi, version: 1}
+- {name: uaa, version: 3}
+- {name: bpm, version: 1}
+instance_groups:
+- name: api
+  azs: [z3]
+  networks:
+  - {name: default, static_ips: [10.0.0.1]}
+  jobs:
+  - name: cc
+    release: capi
+    properties: {a: 1, c: 3}
+  - name: nginx
+    release: capi
+- name: uaa
+  instances: 1
+`)))
+	})
+
+	It("deletes list items and map values with delete directive", func() {
+		res, err := StrategicMergeOp{
+			Path: MustNewPointerFromString(""),
+			Value: parse(`
+releases:
+- {name: uaa, $patch: delete}
+- {name: missing, $patch: delete}
+instance_groups:
+- name: api
+  networks: {$patch: delete}
+  jobs:
+  - {name: nginx, $patch: delete}
+`),
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`
+releases:
+- {name: capi, version: 1}
+instance_groups:
+- name: api
+  azs: [z1, z2]
+  jobs:
+  - name: cc
+    release: capi
+    properties: {a: 1, b: 2}
+- name: uaa
+  instances: 1
+`)))
+	})
+
+	It("replaces maps and lists with replace directive", func() {
+		res, err := StrategicMergeOp{
+			Path: MustNewPointerFromString("/instance_groups/name=api"),
+			Value: parse(`
+networks:
+- $patch: replace
+- {name: private}
+jobs:
+- name: cc
+  properties: {$patch: replace, z: 1}
+`),
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`
+releases:
+- {name: capi, version: 1}
+- {name: uaa, version: 2}
+instance_groups:
+- name: api
+  azs: [z1, z2]
+  networks:
+  - {name: private}
+  jobs:
+  - name: cc
+    release: capi
+    properties: {z: 1}
+  - name: nginx
+    release: capi
+- name: uaa
+  instances: 1
+`)))
+	})
+
+	It("uses merge keys overridden per path", func() {
+		doc := parse(`
+groups:
+- id: a
+  items: [{key: x, val: 1}, {key: y, val: 1}]
+- id: b
+  items: [{key: x, val: 1}]
+tags: [{name: t1, val: 1}]
+`)
+
+		res, err := StrategicMergeOp{
+			Path: MustNewPointerFromString(""),
+			Value: parse(`
+groups:
+- id: a
+  items: [{key: y, val: 2}]
+- id: b
+  items: [{key: z, val: 2}]
+tags: [{name: t2}]
+`),
+			MergeKeys: map[string]string{
+				"/groups":            "id",
+				"/groups/*/items":    "key",
+				"/groups/id=b/items": "",
+				"/tags":              "",
+			},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`
+groups:
+- id: a
+  items: [{key: x, val: 1}, {key: y, val: 2}]
+- id: b
+  items: [{key: z, val: 2}]
+tags: [{name: t2}]
+`)))
+	})
+
+	It("uses merge keys overridden per path within maps with non-string keys", func() {
+		doc := parse(`{ports: {80: [{proto: tcp, open: false}, {proto: udp, open: false}]}}`)
+
+		res, err := StrategicMergeOp{
+			Path:      MustNewPointerFromString(""),
+			Value:     parse(`{ports: {80: [{proto: tcp, open: true}]}}`),
+			MergeKeys: map[string]string{"/ports/80": "proto"},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`{ports: {80: [{proto: tcp, open: true}, {proto: udp, open: false}]}}`)))
+	})
+
+	It("applies merge keys to optional paths", func() {
+		doc := parse(`groups: [{id: a, val: 1}, {id: b, val: 1}]`)
+
+		res, err := StrategicMergeOp{
+			Path:      MustNewPointerFromString("/groups?"),
+			Value:     []interface{}{parse(`{id: b, val: 2}`)},
+			MergeKeys: map[string]string{"/groups": "id"},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`groups: [{id: a, val: 1}, {id: b, val: 2}]`)))
+
+		res, err = StrategicMergeOp{
+			Path:      MustNewPointerFromString(""),
+			Value:     parse(`groups: [{id: b, val: 3}]`),
+			MergeKeys: map[string]string{"/groups?": "id"},
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(parse(`groups: [{id: a, val: 1}, {id: b, val: 3}]`)))
+	})
+
+	It("removes value with delete directive at path", func() {
+		res, err := StrategicMergeOp{
+			Path:  MustNewPointerFromString("/instance_groups/name=uaa"),
+			Value: parse(`$patch: delete`),
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(map[interface{}]interface{})["instance_groups"]).To(HaveLen(1))
+
+		_, err = StrategicMergeOp{Path: MustNewPointerFromString(""), Value: parse(`$patch: delete`)}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Cannot remove entire document"))
+	})
+
+	It("replaces lists whose items are missing merge key", func() {
+		res, err := StrategicMergeOp{
+			Path:  MustNewPointerFromString(""),
+			Value: parse(`releases: [{version: 5}]`),
+		}.Apply(doc)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(map[interface{}]interface{})["releases"]).To(Equal([]interface{}{
+			map[interface{}]interface{}{"version": 5},
+		}))
+	})
+
+	It("does not modify value for future operations", func() {
+		val := parse(`instance_groups: [{name: api, jobs: [{name: new}]}]`)
+
+		_, err := StrategicMergeOp{Path: MustNewPointerFromString(""), Value: val}.Apply(doc)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(val).To(Equal(parse(`instance_groups: [{name: api, jobs: [{name: new}]}]`)))
+	})
+
+	It("returns an error for unknown directive", func() {
+		_, err := StrategicMergeOp{
+			Path:  MustNewPointerFromString(""),
+			Value: parse(`instance_groups: [{name: api, $patch: unknown}]`),
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(
+			"Expected to find one of the following directives: 'delete', 'replace' or 'merge' but found 'unknown' for path '/instance_groups/name=api'"))
+	})
+
+	It("returns an error if path cannot be found", func() {
+		_, err := StrategicMergeOp{
+			Path:  MustNewPointerFromString("/missing"),
+			Value: map[interface{}]interface{}{},
+		}.Apply(doc)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Expected to find a map key 'missing'"))
+	})
+})
+
+var _ = Describe("Diff.CalculateStrategicMergePatch", func() {
+	parse := func(str string) interface{} {
+		var obj interface{}
+		Expect(yaml.Unmarshal([]byte(str), &obj)).To(Succeed())
+		return obj
+	}
+
+	testPatch := func(left, right, expectedPatch interface{}, mergeKeys map[string]string) {
+		patch := Diff{Left: left, Right: right}.CalculateStrategicMergePatch(mergeKeys)
+		Expect(patch).To(Equal(expectedPatch))
+
+		res, err := StrategicMergeOp{
+			Path:      MustNewPointerFromString(""),
+			Value:     patch,
+			MergeKeys: mergeKeys,
+		}.Apply(left)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(right))
+	}
+
+	It("returns empty patch for same documents", func() {
+		testPatch(parse(`a: 1`), parse(`a: 1`), map[interface{}]interface{}{}, nil)
+	})
+
+	It("returns document itself for same documents that are not maps", func() {
+		testPatch([]interface{}{1, 2}, []interface{}{1, 2}, []interface{}{1, 2}, nil)
+		testPatch("a", "a", "a", nil)
+	})
+
+	It("returns changed, added and deleted items of lists keyed by name", func() {
+		testPatch(
+			parse(`
+instance_groups:
+- name: api
+  azs: [z1]
+  jobs: [{name: cc, properties: {a: 1}}, {name: nginx}]
+- name: uaa
+- name: db
+`),
+			parse(`
+instance_groups:
+- name: api
+  azs: [z1, z2]
+  jobs: [{name: cc, properties: {b: 2}}, {name: nginx}]
+- name: db
+  instances: 2
+- name: new
+`),
+			parse(`
+instance_groups:
+- name: api
+  azs: [z1, z2]
+  jobs: [{name: cc, properties: {a: null, b: 2}}]
+- {name: db, instances: 2}
+- {name: new}
+- {name: uaa, $patch: delete}
+`),
+			nil,
+		)
+	})
+
+	It("replaces keyed lists whose items were reordered", func() {
+		testPatch(
+			parse(`jobs: [{name: a}, {name: b}]`),
+			parse(`jobs: [{name: b}, {name: a}]`),
+			parse(`jobs: [{$patch: replace}, {name: b}, {name: a}]`),
+			nil,
+		)
+	})
+
+	It("uses merge keys overridden per path", func() {
+		testPatch(
+			parse(`items: [{id: 1, val: a}, {id: 2, val: b}]`),
+			parse(`items: [{id: 1, val: c}]`),
+			parse(`items: [{id: 1, val: c}, {id: 2, $patch: delete}]`),
+			map[string]string{"/items": "id"},
+		)
+	})
+
+	It("returns right document if either document is not a map", func() {
+		testPatch([]interface{}{1}, []interface{}{2}, []interface{}{2}, nil)
+		testPatch("a", map[interface{}]interface{}{"a": 1}, map[interface{}]interface{}{"a": 1}, nil)
+	})
+	It("only returns changes selected by ignore and include patterns", func() {
+		left := parse(`
+jobs:
+- {name: a, props: {x: 1, y: 1}}
+- {name: b, props: {x: 1, y: 1}}
+- {name: c, props: {x: 1, y: 1}}
+tags: [a]
+`)
+		right := parse(`
+jobs:
+- {name: a, props: {x: 2, y: 2}}
+- {name: c, props: {x: 1, y: 1}}
+- {name: d, props: {x: 1, y: 1}}
+tags: [b]
+`)
+
+		patch := Diff{
+			Left:    left,
+			Right:   right,
+			Ignore:  []Pointer{MustNewPointerFromString("/jobs/name=b")},
+			Include: []Pointer{MustNewPointerFromString("/jobs/*/props/x")},
+		}.CalculateStrategicMergePatch(nil)
+
+		Expect(patch).To(Equal(parse(`
+jobs:
+- {name: a, props: {x: 2}}
+- {name: d, props: {x: 1}}
+`)))
+
+		patch = Diff{
+			Left:    left,
+			Right:   right,
+			Include: []Pointer{MustNewPointerFromString("/jobs/name=b/props")},
+		}.CalculateStrategicMergePatch(nil)
+
+		Expect(patch).To(Equal(parse(`jobs: [{name: b, props: null}]`)))
+	})
+
+	It("does not change document if all changes are skipped", func() {
+		diff := Diff{Ignore: []Pointer{MustNewPointerFromString("")}}
+
+		diff.Left, diff.Right = parse(`a: 1`), parse(`a: 2`)
+		Expect(diff.CalculateStrategicMergePatch(nil)).To(Equal(map[interface{}]interface{}{}))
+
+		diff.Left, diff.Right = "a", "b"
+		Expect(diff.CalculateStrategicMergePatch(nil)).To(Equal("a"))
+	})
+})
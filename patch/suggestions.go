@@ -0,0 +1,125 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const maxSuggestions = 3
+
+// suggest returns candidates closest to the target by edit distance (ignoring case)
+func suggest(target string, candidates []string) []string {
+	target = strings.ToLower(target)
+
+	// allow one typo per three characters so that short names are not matched by anything
+	maxDist := len([]rune(target)) / 3
+
+	bestDist := maxDist + 1
+	var best []string
+
+	seen := map[string]bool{}
+
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		dist := editDistance(target, strings.ToLower(candidate))
+		switch {
+		case dist > maxDist:
+			continue
+		case dist < bestDist:
+			bestDist, best = dist, []string{candidate}
+		case dist == bestDist:
+			best = append(best, candidate)
+		}
+	}
+
+	sort.Strings(best)
+
+	if len(best) > maxSuggestions {
+		best = best[:maxSuggestions]
+	}
+
+	return best
+}
+
+// editDistance counts insertions, deletions, substitutions and transpositions of adjacent characters
+func editDistance(left, right string) int {
+	a, b := []rune(left), []rune(right)
+
+	dists := make([][]int, len(a)+1)
+	for i := range dists {
+		dists[i] = make([]int, len(b)+1)
+		dists[i][0] = i
+	}
+	for j := range dists[0] {
+		dists[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			dists[i][j] = min(min(dists[i-1][j]+1, dists[i][j-1]+1), dists[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				dists[i][j] = min(dists[i][j], dists[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return dists[len(a)][len(b)]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// matchingValues returns values that could be matched by the last token of the path within an array;
+// values hidden by DefaultRedactionPolicy are skipped
+func matchingValues(path Pointer, array reflect.Value) []string {
+	var values []string
+
+	tokens := path.Tokens()
+	parentTokens := tokens[:len(tokens)-1]
+
+	itemPath := func(i int, childTokens ...Token) Pointer {
+		return NewPointer(append(append(append([]Token{}, parentTokens...), IndexToken{Index: i}), childTokens...))
+	}
+
+	for i := 0; i < array.Len(); i++ {
+		item := dereference(array.Index(i))
+
+		switch typedToken := tokens[len(tokens)-1].(type) {
+		case MatchingIndexToken:
+			if item.Kind() != reflect.Map {
+				continue
+			}
+			if v := item.MapIndex(reflect.ValueOf(typedToken.Key)); v.IsValid() {
+				val := dereference(v).Interface()
+				if !DefaultRedactionPolicy().Redacts(itemPath(i, KeyToken{Key: typedToken.Key}), val) {
+					values = append(values, fmt.Sprintf("%v", val))
+				}
+			}
+
+		case MatchingValueToken:
+			if isScalar(item) {
+				if !DefaultRedactionPolicy().Redacts(itemPath(i), item.Interface()) {
+					values = append(values, fmt.Sprintf("%v", item.Interface()))
+				}
+			}
+		}
+	}
+
+	return values
+}
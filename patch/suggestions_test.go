@@ -0,0 +1,101 @@
+package patch_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("Suggestions", func() {
+	doc := map[interface{}]interface{}{
+		"instance_groups": []interface{}{
+			map[interface{}]interface{}{"name": "api", "instances": 1},
+			map[interface{}]interface{}{"name": "worker", "instances": 2},
+		},
+		"azs":      []interface{}{"z1", "z2"},
+		"networks": []interface{}{"default"},
+	}
+
+	It("suggests similar map keys when key is missing", func() {
+		_, err := FindOp{Path: MustNewPointerFromString("/instance_group")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find a map key 'instance_group' for path '/instance_group' " +
+			"(found map keys: 'azs', 'instance_groups', 'networks'; did you mean 'instance_groups'?)"))
+
+		typedErr, ok := err.(OpMissingMapKeyErr)
+		Expect(ok).To(BeTrue())
+		Expect(typedErr.Suggestions).To(Equal([]string{"instance_groups"}))
+	})
+
+	It("suggests map keys regardless of case and transposed characters", func() {
+		_, err := RemoveOp{Path: MustNewPointerFromString("/Netwroks")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(OpMissingMapKeyErr).Suggestions).To(Equal([]string{"networks"}))
+	})
+
+	It("does not suggest map keys that are not similar", func() {
+		_, err := ReplaceOp{Path: MustNewPointerFromString("/releases/name=uaa/version"), Value: 1}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find a map key 'releases' for path '/releases' " +
+			"(found map keys: 'azs', 'instance_groups', 'networks')"))
+		Expect(err.(OpMissingMapKeyErr).Suggestions).To(BeEmpty())
+	})
+
+	It("suggests similar values of matching key when no items match", func() {
+		_, err := FindOp{Path: MustNewPointerFromString("/instance_groups/name=wroker")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find exactly one matching array item " +
+			"for path '/instance_groups/name=wroker' but found 0 (did you mean 'name=worker'?)"))
+
+		typedErr, ok := err.(OpMultipleMatchingIndexErr)
+		Expect(ok).To(BeTrue())
+		Expect(typedErr.Suggestions).To(Equal([]string{"name=worker"}))
+	})
+
+	It("suggests similar values when no items match", func() {
+		_, err := RemoveOp{Path: MustNewPointerFromString("/networks/=defualt")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find exactly one matching array item " +
+			"for path '/networks/=defualt' but found 0 (did you mean 'default'?)"))
+	})
+
+	It("does not suggest values hidden by redaction policy", func() {
+		doc := map[interface{}]interface{}{
+			"users":     []interface{}{map[interface{}]interface{}{"password": "s3cr3tpass"}},
+			"passwords": []interface{}{"s3cr3tpass"},
+		}
+
+		_, err := FindOp{Path: MustNewPointerFromString("/users/password=s3cr3tpasx")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find exactly one matching array item " +
+			"for path '/users/password=s3cr3tpasx' but found 0"))
+		Expect(err.(OpMultipleMatchingIndexErr).Suggestions).To(BeEmpty())
+
+		bytes, err := json.Marshal(err)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes)).ToNot(ContainSubstring("s3cr3tpass"))
+
+		_, err = FindOp{Path: MustNewPointerFromString("/passwords/=s3cr3tpasx")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(OpMultipleMatchingIndexErr).Suggestions).To(BeEmpty())
+	})
+
+	It("does not suggest values when multiple items match", func() {
+		doc := []interface{}{"z1", "z1"}
+
+		_, err := FindOp{Path: MustNewPointerFromString("/=z1")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find exactly one matching array item for path '/=z1' but found 2"))
+		Expect(err.(OpMultipleMatchingIndexErr).Suggestions).To(BeEmpty())
+	})
+
+	It("includes valid index range when index is out of bounds", func() {
+		_, err := FindOp{Path: MustNewPointerFromString("/azs/2")}.Apply(doc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find array index '2' but found array of length '2' " +
+			"for path '/azs/2' (valid indices are 0 to 1 or -2 to -1)"))
+	})
+})
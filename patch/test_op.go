@@ -1,52 +1,257 @@
 package patch
 
 import (
+	"context"
 	"fmt"
-	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
 type TestOp struct {
 	Path   Pointer
 	Value  interface{}
 	Absent bool
+
+	Equality Equality // determines how found value is compared to Value and checked for absence
+
+	// Assertions are checked in addition to value comparison;
+	// value is only compared if it's set or if there are no assertions
+	Exists   bool
+	Is       string // one of JSON Schema types (e.g. string, integer, object)
+	Matches  string // regular expression matched against string value
+	Min      *float64
+	Max      *float64
+	Contains []interface{} // items expected to be found in array
+	Keys     []string      // keys expected to be found in map
+	Length   *int          // length of string, array or map
+}
+
+var testOpTypes = []string{"null", "boolean", "string", "number", "integer", "object", "array"}
+
+func isTestOpType(name string) bool {
+	for _, t := range testOpTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (op TestOp) Apply(doc interface{}) (interface{}, error) {
+	return op.apply(context.Background(), doc)
+}
+
+func (op TestOp) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	return op.apply(ctx, doc)
+}
+
+func (op TestOp) apply(ctx context.Context, doc interface{}) (interface{}, error) {
 	if op.Absent {
-		return op.checkAbsence(doc)
+		return op.checkAbsence(ctx, doc)
 	}
-	return op.checkValue(doc)
+	return op.checkValue(ctx, doc)
 }
 
-func (op TestOp) checkAbsence(doc interface{}) (interface{}, error) {
-	_, err := FindOp{Path: op.Path}.Apply(doc)
+func (op TestOp) checkAbsence(ctx context.Context, doc interface{}) (interface{}, error) {
+	foundVal, err := FindOp{Path: op.Path}.ApplyContext(ctx, doc)
 	if err != nil {
-		if typedErr, ok := err.(OpMissingIndexErr); ok {
-			if typedErr.Path.String() == op.Path.String() {
-				return doc, nil
-			}
-		}
-		if typedErr, ok := err.(OpMissingMapKeyErr); ok {
-			if typedErr.Path.String() == op.Path.String() {
-				return doc, nil
-			}
+		if op.isMissing(err) {
+			return doc, nil
 		}
 		return nil, err
 	}
 
-	return nil, fmt.Errorf("Expected to not find '%s'", op.Path)
+	if op.Equality.absent(foundVal) {
+		return doc, nil
+	}
+
+	return nil, OpTestUnexpectedValueErr{Path: op.Path, Actual: foundVal}
 }
 
-func (op TestOp) checkValue(doc interface{}) (interface{}, error) {
-	foundVal, err := FindOp{Path: op.Path}.Apply(doc)
+func (op TestOp) isMissing(err error) bool {
+	switch typedErr := err.(type) {
+	case OpMissingIndexErr:
+		return typedErr.Path.String() == op.Path.String()
+	case OpMissingMapKeyErr:
+		return typedErr.Path.String() == op.Path.String()
+	case OpMultipleMatchingIndexErr:
+		return len(typedErr.Idxs) == 0 && typedErr.Path.String() == op.Path.String()
+	default:
+		return false
+	}
+}
+
+func (op TestOp) checkValue(ctx context.Context, doc interface{}) (interface{}, error) {
+	foundVal, err := FindOp{Path: op.Path}.ApplyContext(ctx, doc)
 	if err != nil {
+		if op.Exists && op.isMissing(err) {
+			return nil, OpTestMissingValueErr{Path: op.Path, Err: err}
+		}
+		// expected value may be considered the same as a missing value
+		if !op.hasAssertions() && op.Equality.absent(op.Value) && op.isMissing(err) {
+			return doc, nil
+		}
 		return nil, err
 	}
 
-	if !reflect.DeepEqual(foundVal, op.Value) {
-		return nil, fmt.Errorf("Found value does not match expected value")
+	if op.comparesValue() && !op.Equality.Equal(foundVal, op.Value) {
+		return nil, OpTestValueMismatchErr{Path: op.Path, Expected: op.Value, Actual: foundVal, Equality: op.Equality}
+	}
+
+	err = op.checkAssertions(foundVal)
+	if err != nil {
+		return nil, err
 	}
 
 	// Return same input document
 	return doc, nil
 }
+
+// hasAssertions returns true if any assertion besides value comparison and absence is set
+func (op TestOp) hasAssertions() bool {
+	return op.Exists || len(op.Is) > 0 || len(op.Matches) > 0 || op.Min != nil || op.Max != nil ||
+		op.Contains != nil || op.Keys != nil || op.Length != nil
+}
+
+// comparesValue returns true if found value is expected to equal Value
+func (op TestOp) comparesValue() bool {
+	return !op.Absent && (op.Value != nil || !op.hasAssertions())
+}
+
+func (op TestOp) checkAssertions(val interface{}) error {
+	var v schemaValidator
+
+	if len(op.Is) > 0 {
+		actual := v.typeOf(val)
+		if actual != op.Is && !(op.Is == "number" && actual == "integer") {
+			return op.assertionErr("is", op.Is, val, "Expected value at path '%s' to be of type '%s' but found %s of type '%s'",
+				op.Path, op.Is, op.fmtValue(val), actual)
+		}
+	}
+
+	if len(op.Matches) > 0 {
+		re, err := regexp.Compile(op.Matches)
+		if err != nil {
+			return fmt.Errorf("Expected valid regular expression '%s': %s", op.Matches, err)
+		}
+
+		str, ok := val.(string)
+		if !ok || !re.MatchString(str) {
+			return op.assertionErr("matches", op.Matches, val, "Expected value at path '%s' to be a string matching '%s' but found %s",
+				op.Path, op.Matches, op.fmtValue(val))
+		}
+	}
+
+	if op.Min != nil || op.Max != nil {
+		num, ok := v.number(val)
+		if !ok {
+			return op.assertionErr(op.rangeAssertion(), "number", val,
+				"Expected value at path '%s' to be a number but found %s", op.Path, op.fmtValue(val))
+		}
+
+		if op.Min != nil && num < *op.Min {
+			return op.assertionErr("min", *op.Min, val, "Expected value at path '%s' to be greater than or equal to %v but found %s",
+				op.Path, *op.Min, op.fmtValue(val))
+		}
+
+		if op.Max != nil && num > *op.Max {
+			return op.assertionErr("max", *op.Max, val, "Expected value at path '%s' to be less than or equal to %v but found %s",
+				op.Path, *op.Max, op.fmtValue(val))
+		}
+	}
+
+	if op.Contains != nil {
+		items, ok := val.([]interface{})
+		if !ok {
+			return op.assertionErr("contains", "array", val,
+				"Expected value at path '%s' to be an array but found %s", op.Path, op.fmtValue(val))
+		}
+
+		var missing []interface{}
+
+		for _, expected := range op.Contains {
+			found := false
+			for _, item := range items {
+				if v.equal(item, expected) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				missing = append(missing, expected)
+			}
+		}
+
+		if len(missing) > 0 {
+			return op.assertionErr("contains", missing, val, "Expected array at path '%s' to contain %s but found %s",
+				op.Path, op.fmtValue(missing), op.fmtValue(val))
+		}
+	}
+
+	if op.Keys != nil {
+		obj, ok := val.(map[interface{}]interface{})
+		if !ok {
+			return op.assertionErr("keys", "map", val,
+				"Expected value at path '%s' to be a map but found %s", op.Path, op.fmtValue(val))
+		}
+
+		var missing, actual []string
+
+		for _, key := range op.Keys {
+			if _, found := obj[key]; !found {
+				missing = append(missing, key)
+			}
+		}
+
+		for key := range obj {
+			actual = append(actual, fmt.Sprintf("%v", key))
+		}
+
+		sort.Strings(actual)
+
+		if len(missing) > 0 {
+			return op.assertionErr("keys", missing, actual, "Expected map at path '%s' to have keys '%s' but found keys '%s'",
+				op.Path, strings.Join(missing, "', '"), strings.Join(actual, "', '"))
+		}
+	}
+
+	if op.Length != nil {
+		var length int
+
+		switch typedVal := val.(type) {
+		case string:
+			length = utf8.RuneCountInString(typedVal)
+		case []interface{}:
+			length = len(typedVal)
+		case map[interface{}]interface{}:
+			length = len(typedVal)
+		default:
+			return op.assertionErr("length", *op.Length, val, "Expected value at path '%s' to be a string, array or map but found %s",
+				op.Path, op.fmtValue(val))
+		}
+
+		if length != *op.Length {
+			return op.assertionErr("length", *op.Length, val, "Expected value at path '%s' to have length %d but found length %d (%s)",
+				op.Path, *op.Length, length, op.fmtValue(val))
+		}
+	}
+
+	return nil
+}
+
+func (op TestOp) assertionErr(assertion string, expected, actual interface{}, msg string, args ...interface{}) error {
+	return OpTestAssertionErr{Path: op.Path, Assertion: assertion, Expected: expected, Actual: actual, Message: fmt.Sprintf(msg, args...)}
+}
+
+func (op TestOp) rangeAssertion() string {
+	if op.Min != nil {
+		return "min"
+	}
+	return "max"
+}
+
+func (op TestOp) fmtValue(val interface{}) string {
+	return DefaultRedactionPolicy().fmtValue(op.Path, val)
+}
@@ -1,6 +1,9 @@
 package patch_test
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -34,7 +37,7 @@ var _ = Describe("TestOp.Apply", func() {
 			}.Apply([]interface{}{1, 2, 3})
 
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("Found value does not match expected value"))
+			Expect(err.Error()).To(Equal("Found value at path '/0' does not match expected value:\n  /0: expected 2, found 1"))
 
 			_, err = TestOp{
 				Path:  MustNewPointerFromString("/0"),
@@ -42,7 +45,55 @@ var _ = Describe("TestOp.Apply", func() {
 			}.Apply([]interface{}{nil})
 
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("Found value does not match expected value"))
+			Expect(err.Error()).To(Equal("Found value at path '/0' does not match expected value:\n  /0: expected 2, found null"))
+		})
+
+		It("returns a typed error listing differences between expected and found values", func() {
+			_, err := TestOp{
+				Path: MustNewPointerFromString("/a"),
+				Value: map[interface{}]interface{}{
+					"changed": 1,
+					"missing": "x",
+					"items":   []interface{}{1, 2, 3, 4},
+				},
+			}.Apply(map[interface{}]interface{}{
+				"a": map[interface{}]interface{}{
+					"changed": 2,
+					"extra":   []interface{}{true},
+					"items":   []interface{}{1},
+				},
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(OpTestValueMismatchErr{}))
+			Expect(err.(OpTestValueMismatchErr).Path).To(Equal(MustNewPointerFromString("/a")))
+			Expect(err.(OpTestValueMismatchErr).Actual).To(HaveKeyWithValue("changed", 2))
+
+			Expect(err.Error()).To(Equal(`Found value at path '/a' does not match expected value:
+  /a/changed: expected 1, found 2
+  /a/extra: expected nothing, found [true]
+  /a/items/1: expected 2, found nothing
+  /a/items/2: expected 3, found nothing
+  /a/items/3: expected 4, found nothing
+  /a/missing: expected "x", found nothing`))
+		})
+
+		It("limits number and size of listed differences", func() {
+			expected := map[interface{}]interface{}{}
+			actual := map[interface{}]interface{}{}
+
+			for i := 0; i < 15; i++ {
+				expected[fmt.Sprintf("key%02d", i)] = i
+				actual[fmt.Sprintf("key%02d", i)] = strings.Repeat("x", 100)
+			}
+
+			_, err := TestOp{Path: MustNewPointerFromString(""), Value: expected}.Apply(actual)
+			Expect(err).To(HaveOccurred())
+
+			lines := strings.Split(err.Error(), "\n")
+			Expect(lines).To(HaveLen(12))
+			Expect(lines[1]).To(Equal(`  /key00: expected 0, found "` + strings.Repeat("x", 79) + "..."))
+			Expect(lines[11]).To(Equal("  ... and 5 more difference(s)"))
 		})
 	})
 
@@ -63,6 +114,22 @@ var _ = Describe("TestOp.Apply", func() {
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(res).To(Equal(map[interface{}]interface{}{"b": 123}))
+
+			res, err = TestOp{
+				Path:   MustNewPointerFromString("/name=a"),
+				Absent: true,
+			}.Apply([]interface{}{map[interface{}]interface{}{"name": "b"}})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{map[interface{}]interface{}{"name": "b"}}))
+
+			res, err = TestOp{
+				Path:   MustNewPointerFromString("/=z3"),
+				Absent: true,
+			}.Apply([]interface{}{"z1", "z2"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"z1", "z2"}))
 		})
 
 		It("returns an error if parent key is absent", func() {
@@ -72,7 +139,7 @@ var _ = Describe("TestOp.Apply", func() {
 			}.Apply([]interface{}{})
 
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("Expected to find array index '0' but found array of length '0' for path '/0'"))
+			Expect(err.Error()).To(Equal("Expected to find array index '0' but found array of length '0' for path '/0' (array is empty)"))
 
 			_, err = TestOp{
 				Path:   MustNewPointerFromString("/a/b"),
@@ -99,6 +166,160 @@ var _ = Describe("TestOp.Apply", func() {
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("Expected to not find '/a'"))
+
+			_, err = TestOp{
+				Path:   MustNewPointerFromString("/=z1"),
+				Absent: true,
+			}.Apply([]interface{}{"z1", "z2"})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to not find '/=z1'"))
+		})
+	})
+
+	Describe("equality", func() {
+		It("compares values using given equality", func() {
+			doc := map[interface{}]interface{}{"a": 1.0, "b": map[interface{}]interface{}{"c": nil}}
+
+			_, err := TestOp{Path: MustNewPointerFromString("/a"), Value: 1}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+
+			_, err = TestOp{Path: MustNewPointerFromString("/a"), Value: 1, Equality: Equality{NormalizeNumbers: true}}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = TestOp{
+				Path:     MustNewPointerFromString("/b"),
+				Value:    map[interface{}]interface{}{},
+				Equality: Equality{NullAsAbsent: true},
+			}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = TestOp{Path: MustNewPointerFromString("/a"), Value: 2, Equality: Equality{NormalizeNumbers: true}}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Found value at path '/a' does not match expected value:\n  /a: expected 2, found 1"))
+		})
+
+		It("treats null or empty values as absent", func() {
+			doc := map[interface{}]interface{}{"a": nil, "b": []interface{}{}}
+			eq := Equality{NullAsAbsent: true, IgnoreEmpty: true}
+
+			for _, path := range []string{"/a", "/b", "/c"} {
+				_, err := TestOp{Path: MustNewPointerFromString(path), Absent: true, Equality: eq}.Apply(doc)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			_, err := TestOp{Path: MustNewPointerFromString("/c"), Value: nil, Equality: eq}.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = TestOp{Path: MustNewPointerFromString("/c"), Value: nil}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+
+			_, err = TestOp{Path: MustNewPointerFromString("/a"), Absent: true}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("assertions", func() {
+		doc := map[interface{}]interface{}{
+			"name":  "web-1",
+			"count": 3,
+			"ratio": 0.5,
+			"items": []interface{}{"a", "b", 1},
+			"props": map[interface{}]interface{}{"a": 1, "b": 2},
+			"empty": nil,
+		}
+
+		num := func(n float64) *float64 { return &n }
+		length := func(n int) *int { return &n }
+
+		It("does not error if all assertions pass", func() {
+			ops := Ops{
+				TestOp{Path: MustNewPointerFromString("/name"), Is: "string", Matches: "^web-\\d+$", Length: length(5)},
+				TestOp{Path: MustNewPointerFromString("/count"), Is: "integer", Min: num(2), Max: num(3)},
+				TestOp{Path: MustNewPointerFromString("/ratio"), Is: "number", Max: num(1)},
+				TestOp{Path: MustNewPointerFromString("/count"), Is: "number"},
+				TestOp{Path: MustNewPointerFromString("/items"), Is: "array", Contains: []interface{}{"b", 1.0}, Length: length(3)},
+				TestOp{Path: MustNewPointerFromString("/props"), Is: "object", Keys: []string{"a", "b"}, Length: length(2)},
+				TestOp{Path: MustNewPointerFromString("/empty"), Exists: true, Is: "null"},
+				TestOp{Path: MustNewPointerFromString("/count"), Value: 3, Min: num(1)},
+			}
+
+			res, err := ops.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(doc))
+		})
+
+		It("returns an error if value does not exist", func() {
+			_, err := TestOp{Path: MustNewPointerFromString("/missing"), Exists: true}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find '/missing'"))
+
+			_, err = TestOp{Path: MustNewPointerFromString("/items/5"), Exists: true}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find '/items/5'"))
+
+			_, err = TestOp{Path: MustNewPointerFromString("/missing/a"), Exists: true}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find a map key 'missing'"))
+		})
+
+		It("returns an error with expected and actual values if assertion fails", func() {
+			tests := map[string]TestOp{
+				"Expected value at path '/name' to be of type 'integer' but found \"web-1\" of type 'string'": TestOp{
+					Path: MustNewPointerFromString("/name"), Is: "integer",
+				},
+				"Expected value at path '/ratio' to be of type 'integer' but found 0.5 of type 'number'": TestOp{
+					Path: MustNewPointerFromString("/ratio"), Is: "integer",
+				},
+				"Expected value at path '/name' to be a string matching '^db-' but found \"web-1\"": TestOp{
+					Path: MustNewPointerFromString("/name"), Matches: "^db-",
+				},
+				"Expected value at path '/count' to be a string matching '^db-' but found 3": TestOp{
+					Path: MustNewPointerFromString("/count"), Matches: "^db-",
+				},
+				"Expected value at path '/count' to be greater than or equal to 4 but found 3": TestOp{
+					Path: MustNewPointerFromString("/count"), Min: num(4),
+				},
+				"Expected value at path '/count' to be less than or equal to 2.5 but found 3": TestOp{
+					Path: MustNewPointerFromString("/count"), Max: num(2.5),
+				},
+				"Expected value at path '/name' to be a number but found \"web-1\"": TestOp{
+					Path: MustNewPointerFromString("/name"), Min: num(1),
+				},
+				"Expected array at path '/items' to contain [\"c\",2] but found [\"a\",\"b\",1]": TestOp{
+					Path: MustNewPointerFromString("/items"), Contains: []interface{}{"a", "c", 2},
+				},
+				"Expected value at path '/props' to be an array but found {\"a\":1,\"b\":2}": TestOp{
+					Path: MustNewPointerFromString("/props"), Contains: []interface{}{"a"},
+				},
+				"Expected map at path '/props' to have keys 'c', 'd' but found keys 'a', 'b'": TestOp{
+					Path: MustNewPointerFromString("/props"), Keys: []string{"a", "c", "d"},
+				},
+				"Expected value at path '/items' to be a map but found [\"a\",\"b\",1]": TestOp{
+					Path: MustNewPointerFromString("/items"), Keys: []string{"a"},
+				},
+				"Expected value at path '/items' to have length 2 but found length 3 ([\"a\",\"b\",1])": TestOp{
+					Path: MustNewPointerFromString("/items"), Length: length(2),
+				},
+				"Expected value at path '/count' to be a string, array or map but found 3": TestOp{
+					Path: MustNewPointerFromString("/count"), Length: length(2),
+				},
+				"Found value at path '/count' does not match expected value:\n  /count: expected 4, found 3": TestOp{
+					Path: MustNewPointerFromString("/count"), Value: 4, Min: num(1),
+				},
+			}
+
+			for msg, op := range tests {
+				_, err := op.Apply(doc)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(msg))
+			}
+		})
+
+		It("returns an error if regular expression is invalid", func() {
+			_, err := TestOp{Path: MustNewPointerFromString("/name"), Matches: "("}.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected valid regular expression '('"))
 		})
 	})
 })
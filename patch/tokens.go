@@ -20,6 +20,13 @@ type MatchingIndexToken struct {
 	Modifiers []Modifier
 }
 
+// MatchingValueToken matches scalar array items by value (e.g. '/azs/=z1')
+type MatchingValueToken struct {
+	Value     string
+	Optional  bool
+	Modifiers []Modifier
+}
+
 type KeyToken struct {
 	Key      string
 	Optional bool
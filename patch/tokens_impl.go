@@ -4,12 +4,14 @@ var _ Token = RootToken{}
 var _ Token = IndexToken{}
 var _ Token = AfterLastIndexToken{}
 var _ Token = MatchingIndexToken{}
+var _ Token = MatchingValueToken{}
 var _ Token = KeyToken{}
 
 func (RootToken) _token()           {}
 func (IndexToken) _token()          {}
 func (AfterLastIndexToken) _token() {}
 func (MatchingIndexToken) _token()  {}
+func (MatchingValueToken) _token()  {}
 func (KeyToken) _token()            {}
 
 var _ Modifier = PrevModifier{}
@@ -0,0 +1,131 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+)
+
+// ValidateOp checks that value at a path matches schema
+type ValidateOp struct {
+	Path   Pointer
+	Schema Schema
+}
+
+func (op ValidateOp) Apply(doc interface{}) (interface{}, error) {
+	val, err := FindOp{Path: op.Path}.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := op.violations(val); len(violations) > 0 {
+		return nil, SchemaValidationErr{op.Path, violations}
+	}
+
+	return doc, nil
+}
+
+// violations returns schema violations with paths within the document
+func (op ValidateOp) violations(val interface{}) []SchemaViolation {
+	violations := op.Schema.Validate(val)
+
+	for i, violation := range violations {
+		violations[i].Path = op.Path.Concat(violation.Path)
+	}
+
+	return violations
+}
+
+// ValidatedOps validates the document after all operations are applied
+// and attributes each violation to the operation that introduced it
+type ValidatedOps struct {
+	Ops      Ops
+	Validate ValidateOp
+}
+
+func (o ValidatedOps) Apply(doc interface{}) (interface{}, error) {
+	return o.ApplyContext(context.Background(), doc)
+}
+
+func (o ValidatedOps) ApplyContext(ctx context.Context, doc interface{}) (interface{}, error) {
+	original, err := cloneDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.Ops.ApplyContext(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	violations, err := o.violations(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(violations) == 0 {
+		return result, nil
+	}
+
+	// Replay operations only when document is invalid to find which ones introduced violations
+	introducedBy, err := o.introducedBy(ctx, original)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, violation := range violations {
+		if index, found := introducedBy[violation.key()]; found {
+			violations[i].Index = index
+		}
+	}
+
+	return nil, SchemaValidationErr{o.Validate.Path, violations}
+}
+
+func (o ValidatedOps) introducedBy(ctx context.Context, doc interface{}) (map[string]int, error) {
+	introducedBy := map[string]int{}
+
+	previous, err := o.violations(doc)
+	if err != nil {
+		previous = nil // validated path may be created by operations
+	}
+
+	for i, op := range o.Ops {
+		doc, err = applyContext(ctx, op, doc)
+		if err != nil {
+			return nil, fmt.Errorf("Operation [%d]: %w", i, err)
+		}
+
+		current, err := o.violations(doc)
+		if err != nil {
+			current = nil
+		}
+
+		seen := map[string]struct{}{}
+		for _, violation := range previous {
+			seen[violation.key()] = struct{}{}
+		}
+
+		for _, violation := range current {
+			if _, found := seen[violation.key()]; !found {
+				introducedBy[violation.key()] = i
+			}
+		}
+
+		previous = current
+	}
+
+	return introducedBy, nil
+}
+
+func (o ValidatedOps) violations(doc interface{}) ([]SchemaViolation, error) {
+	val, err := FindOp{Path: o.Validate.Path}.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Validate.violations(val), nil
+}
+
+func (v SchemaViolation) key() string {
+	return v.Path.String() + "\n" + v.Message
+}
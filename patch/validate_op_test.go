@@ -0,0 +1,128 @@
+package patch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gstackio/go-patch/patch"
+)
+
+var _ = Describe("ValidateOp.Apply", func() {
+	schema := Schema{Definition: map[interface{}]interface{}{
+		"properties": map[interface{}]interface{}{
+			"instances": map[interface{}]interface{}{"type": "integer"},
+		},
+	}}
+
+	It("returns document if value at path matches schema", func() {
+		doc := map[interface{}]interface{}{"ig": map[interface{}]interface{}{"instances": 1}}
+
+		res, err := ValidateOp{Path: MustNewPointerFromString("/ig"), Schema: schema}.Apply(doc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(doc))
+	})
+
+	It("returns an error with paths of violations within the document", func() {
+		doc := map[interface{}]interface{}{
+			"instance_groups": []interface{}{
+				map[interface{}]interface{}{"name": "api", "instances": "x"},
+			},
+		}
+
+		_, err := GroupOp{
+			Path: MustNewPointerFromString("/instance_groups"),
+			Ops:  Ops{ValidateOp{Path: MustNewPointerFromString("/name=api"), Schema: schema}},
+		}.Apply(doc)
+
+		Expect(err).To(Equal(SchemaValidationErr{
+			Path: MustNewPointerFromString("/instance_groups/name=api"),
+			Violations: []SchemaViolation{{
+				Path:    MustNewPointerFromString("/instance_groups/name=api/instances"),
+				Message: "Expected value of type 'integer' but found 'string'",
+				Index:   -1,
+			}},
+		}))
+
+		Expect(err.Error()).To(Equal("Expected value at path '/instance_groups/name=api' to match schema but found 1 violation(s):\n" +
+			"  - '/instance_groups/name=api/instances': Expected value of type 'integer' but found 'string'"))
+	})
+
+	It("returns an error if path cannot be found", func() {
+		_, err := ValidateOp{Path: MustNewPointerFromString("/missing"), Schema: schema}.Apply(map[interface{}]interface{}{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find a map key 'missing' for path '/missing' (found no other map keys)"))
+	})
+})
+
+var _ = Describe("ValidatedOps.Apply", func() {
+	var validate ValidateOp
+
+	BeforeEach(func() {
+		validate = ValidateOp{
+			Path: MustNewPointerFromString(""),
+			Schema: Schema{Definition: map[interface{}]interface{}{
+				"required": []interface{}{"name"},
+				"properties": map[interface{}]interface{}{
+					"instances": map[interface{}]interface{}{"minimum": 1},
+					"legacy":    false,
+				},
+			}},
+		}
+	})
+
+	It("returns result if it matches schema", func() {
+		res, err := ValidatedOps{
+			Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 0},
+				ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 2},
+			},
+			Validate: validate,
+		}.Apply(map[interface{}]interface{}{"name": "dep", "instances": 1})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[interface{}]interface{}{"name": "dep", "instances": 2}))
+	})
+
+	It("attributes violations to operations that introduced them", func() {
+		_, err := ValidatedOps{
+			Ops: Ops{
+				ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 0},
+				ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: 2},
+				ReplaceOp{Path: MustNewPointerFromString("/instances"), Value: -1},
+				RemoveOp{Path: MustNewPointerFromString("/name")},
+			},
+			Validate: validate,
+		}.Apply(map[interface{}]interface{}{"name": "dep", "instances": 1, "legacy": true})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.(SchemaValidationErr).Violations).To(Equal([]SchemaViolation{
+			{
+				Path:    MustNewPointerFromString(""),
+				Message: "Expected to find required key 'name'",
+				Index:   3,
+			},
+			{
+				Path:    MustNewPointerFromString("/instances"),
+				Message: "Expected number greater than or equal to 1 but found -1",
+				Index:   2,
+			},
+			{
+				Path:    MustNewPointerFromString("/legacy"),
+				Message: "Expected no value to be allowed by schema 'false'",
+				Index:   -1,
+			},
+		}))
+
+		Expect(err.Error()).To(ContainSubstring("  - '/instances': Expected number greater than or equal to 1 but found -1 (introduced by operation [2])\n"))
+	})
+
+	It("returns an error if operation fails", func() {
+		_, err := ValidatedOps{
+			Ops:      Ops{RemoveOp{Path: MustNewPointerFromString("/missing")}},
+			Validate: validate,
+		}.Apply(map[interface{}]interface{}{"name": "dep"})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Expected to find a map key 'missing'"))
+	})
+})